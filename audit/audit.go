@@ -0,0 +1,74 @@
+// Package audit holds the ecosystem-neutral view of an audited dependency,
+// so results from different manifests and repositories can be aggregated.
+package audit
+
+import (
+	"fmt"
+	"time"
+
+	"Sbom/changelog"
+	"Sbom/version"
+)
+
+// Finding summarises one audited dependency
+type Finding struct {
+	Name           string       `json:"name"`
+	CurrentVersion string       `json:"currentVersion"`
+	LatestVersion  string       `json:"latestVersion"`
+	Status         string       `json:"statusText"`      // Human-readable status; State is the machine-readable one
+	Compared       bool         `json:"compared"`        // Checked against a latest version; false for local paths, branch pins, ...
+	Error          string       `json:"error,omitempty"` // Why the dependency could not be checked
+	UpdateNeeded   bool         `json:"updateNeeded"`
+	Vulnerable     bool         `json:"vulnerable"` // A newer release is flagged as a security fix, or the current version was yanked
+	Archived       bool         `json:"archived"`   // The upstream repository is archived
+	Bump           version.Bump `json:"bump,omitempty"`
+	ReleasesBehind int          `json:"releasesBehind"`       // Releases between the current and the latest version (0 when unknown)
+	Advisories     []string     `json:"advisories,omitempty"` // Why the finding is vulnerable, e.g. the security releases it misses
+	Line           int          `json:"line,omitempty"`       // Line of the declaration in the source manifest (0 when unknown)
+	Manifest       string       `json:"manifest,omitempty"`   // Manifest declaring the dependency when it is not the source's, e.g. a workspace member
+
+	Changelog       []changelog.ReleaseNote  `json:"changelog,omitempty"` // Releases between the current and the latest version, newest first
+	BreakingChanges []changelog.BreakingItem `json:"breakingChanges,omitempty"`
+
+	// Branch- and commit-pinned dependencies only
+	CommitsBehind int       `json:"commitsBehind,omitempty"` // Commits on the upstream default branch the pinned commit lacks
+	PinnedDate    time.Time `json:"pinnedDate,omitzero"`     // Committer date of the pinned commit
+	IncludedIn    string    `json:"includedIn,omitempty"`    // Latest release tag that already contains the pinned commit
+
+	Accepted []BaselineEntry `json:"accepted,omitempty"` // Issues the baseline accepts; they are left out of the summary, and SARIF suppresses them
+}
+
+// UpdateStatus is the status of an available (non-security) update, by how risky the bump is
+func UpdateStatus(bump version.Bump) string {
+	switch bump {
+	case version.Major:
+		return "⚠️ Major Update (breaking changes likely)"
+	case version.Minor:
+		return "⬆️ Minor Update"
+	case version.Patch:
+		return "🩹 Patch Update"
+	}
+	return "⬆️ Update Available"
+}
+
+// ChangeLabel summarises a bump for report tables, e.g. "🔴 major (+4 releases)"
+func ChangeLabel(bump version.Bump, behind int) string {
+	label := ""
+	switch bump {
+	case version.Major:
+		label = "🔴 major"
+	case version.Minor:
+		label = "🟡 minor"
+	case version.Patch:
+		label = "🟢 patch"
+	default:
+		return "-"
+	}
+	switch {
+	case behind == 1:
+		label += " (+1 release)"
+	case behind > 1:
+		label += fmt.Sprintf(" (+%d releases)", behind)
+	}
+	return label
+}
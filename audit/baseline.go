@@ -0,0 +1,220 @@
+package audit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+)
+
+// --- Baseline ---
+
+// defaultBaselineFile is read from the working directory when BASELINE_FILE is not set
+const defaultBaselineFile = "sbom-baseline.json"
+
+// baselineDateLayout is the layout of expiry dates, e.g. "2025-03-31"
+const baselineDateLayout = "2006-01-02"
+
+// BaselineEntry is one accepted issue: a dependency version that may stay vulnerable, archived
+// or outdated until Expires, after which it is reported again
+type BaselineEntry struct {
+	Ecosystem string `json:"ecosystem"`
+	Name      string `json:"name"`
+	Version   string `json:"version"`
+	Issue     State  `json:"issue"` // Vulnerable, Archived or Outdated
+	Reason    string `json:"reason"`
+	Owner     string `json:"owner"`
+	Expires   string `json:"expires"` // First day the issue is reported again (YYYY-MM-DD)
+}
+
+func (e BaselineEntry) key() string {
+	return e.Ecosystem + "|" + e.Name + "|" + e.Version + "|" + string(e.Issue)
+}
+
+// expired reports whether the entry no longer applies on day now; entries without a valid
+// expiry date never apply
+func (e BaselineEntry) expired(now time.Time) bool {
+	expires, err := time.ParseInLocation(baselineDateLayout, e.Expires, now.Location())
+	return err != nil || !now.Before(expires)
+}
+
+// Baseline is the file of accepted issues, e.g.
+// {"entries": [{"ecosystem": "npm", "name": "lodash", "version": "4.17.20", "issue": "vulnerable",
+// "reason": "Not reachable from user input", "owner": "web-team", "expires": "2025-03-31"}]}
+type Baseline struct {
+	Entries []BaselineEntry `json:"entries"`
+}
+
+// BaselinePath is the baseline file in use: BASELINE_FILE, else sbom-baseline.json
+func BaselinePath() string {
+	if path := os.Getenv("BASELINE_FILE"); path != "" {
+		return path
+	}
+	return defaultBaselineFile
+}
+
+// LoadBaseline: Reads a baseline file; a missing file is an empty baseline
+func LoadBaseline(path string) (*Baseline, error) {
+	baseline := &Baseline{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return baseline, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading baseline: %w", err)
+	}
+	if err := json.Unmarshal(data, baseline); err != nil {
+		return nil, fmt.Errorf("error parsing baseline %s: %w", path, err)
+	}
+	return baseline, nil
+}
+
+// Save writes the baseline sorted by ecosystem, name and issue, so it diffs well under review
+func (b *Baseline) Save(path string) error {
+	sort.Slice(b.Entries, func(i, j int) bool { return b.Entries[i].key() < b.Entries[j].key() })
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding baseline: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("error writing baseline %s: %w", path, err)
+	}
+	return nil
+}
+
+// Issues lists what a finding is reported for; each can be accepted separately
+func (f Finding) Issues() []State {
+	var issues []State
+	if f.Vulnerable {
+		issues = append(issues, Vulnerable)
+	}
+	if f.Archived {
+		issues = append(issues, Archived)
+	}
+	if f.UpdateNeeded {
+		issues = append(issues, Outdated)
+	}
+	return issues
+}
+
+// IsAccepted reports whether the baseline accepts the issue of the finding
+func (f Finding) IsAccepted(issue State) bool {
+	for _, entry := range f.Accepted {
+		if entry.Issue == issue {
+			return true
+		}
+	}
+	return false
+}
+
+// apply: Marks the issues of findings that have an unexpired entry, and returns the expired
+// entries that matched a finding; those issues are reported again. Issues marked already are
+// left as they are, and entries without a valid expiry date are skipped (loadBaseline reports them).
+func (b *Baseline) apply(ecosystem string, findings []Finding, now time.Time) []BaselineEntry {
+	entries := make(map[string]BaselineEntry)
+	for _, entry := range b.Entries {
+		if _, err := time.Parse(baselineDateLayout, entry.Expires); err == nil {
+			entries[entry.key()] = entry
+		}
+	}
+
+	var expired []BaselineEntry
+	for i := range findings {
+		finding := &findings[i]
+		for _, issue := range finding.Issues() {
+			entry, ok := entries[BaselineEntry{Ecosystem: ecosystem, Name: finding.Name, Version: finding.CurrentVersion, Issue: issue}.key()]
+			switch {
+			case !ok || finding.IsAccepted(issue):
+			case entry.expired(now):
+				expired = append(expired, entry)
+			default:
+				finding.Accepted = append(finding.Accepted, entry)
+			}
+		}
+	}
+	return expired
+}
+
+// loadBaseline: Reads the baseline in use, if any, and reports its invalid entries. NewReport
+// reads it before any manifest is audited, as Report.Add applies it to every source.
+func (r *Report) loadBaseline() {
+	path := BaselinePath()
+	baseline, err := LoadBaseline(path)
+	if err != nil {
+		r.Errors = append(r.Errors, err.Error())
+		return
+	}
+	for _, entry := range baseline.Entries {
+		if _, err := time.Parse(baselineDateLayout, entry.Expires); err != nil {
+			r.Errors = append(r.Errors, fmt.Sprintf("baseline entry %s %s (%s) has no valid expiry date (YYYY-MM-DD) and is ignored", entry.Name, entry.Version, entry.Issue))
+		}
+	}
+	if len(baseline.Entries) > 0 {
+		r.Baseline = path
+		r.baseline = baseline
+	}
+}
+
+// AcceptedStatus: Appends the accepted issues of a finding to a table's status cell, e.g.
+// "**⚠️ Major Update** 🔕 outdated accepted until 2025-03-31"
+func AcceptedStatus(status string, finding Finding) string {
+	for _, entry := range finding.Accepted {
+		status += fmt.Sprintf(" 🔕 %s accepted until %s", entry.Issue, entry.Expires)
+	}
+	return status
+}
+
+// RecordBaseline: Adds every issue of a JSON report that is not accepted yet to the baseline at
+// path, with the given owner, reason and expiry. Expired entries of the same issue are renewed.
+// Returns the number of entries added or renewed.
+func RecordBaseline(reportFile, path, owner, reason string, expires time.Time) (int, error) {
+	data, err := os.ReadFile(reportFile)
+	if err != nil {
+		return 0, fmt.Errorf("error reading report: %w", err)
+	}
+	var report Report
+	if err := json.Unmarshal(data, &report); err != nil {
+		return 0, fmt.Errorf("error parsing report %s: %w", reportFile, err)
+	}
+
+	baseline, err := LoadBaseline(path)
+	if err != nil {
+		return 0, err
+	}
+	now := time.Now()
+	index := make(map[string]int)
+	for i, entry := range baseline.Entries {
+		index[entry.key()] = i
+	}
+
+	added := 0
+	for _, source := range report.Sources {
+		for _, finding := range source.Findings {
+			for _, issue := range finding.Issues() {
+				entry := BaselineEntry{
+					Ecosystem: source.Ecosystem,
+					Name:      finding.Name,
+					Version:   finding.CurrentVersion,
+					Issue:     issue,
+					Reason:    reason,
+					Owner:     owner,
+					Expires:   expires.Format(baselineDateLayout),
+				}
+				i, exists := index[entry.key()]
+				switch {
+				case !exists:
+					index[entry.key()] = len(baseline.Entries)
+					baseline.Entries = append(baseline.Entries, entry)
+				case baseline.Entries[i].expired(now):
+					baseline.Entries[i] = entry
+				default:
+					continue
+				}
+				added++
+			}
+		}
+	}
+	return added, baseline.Save(path)
+}
@@ -0,0 +1,83 @@
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestReportAddAppliesBaseline(t *testing.T) {
+	tomorrow := time.Now().AddDate(0, 0, 1).Format(baselineDateLayout)
+	yesterday := time.Now().AddDate(0, 0, -1).Format(baselineDateLayout)
+	baseline := &Baseline{Entries: []BaselineEntry{
+		{Ecosystem: "npm", Name: "lodash", Version: "4.17.20", Issue: Vulnerable, Owner: "web", Expires: tomorrow},
+		{Ecosystem: "npm", Name: "left-pad", Version: "1.3.0", Issue: Outdated, Owner: "web", Expires: yesterday},
+		{Ecosystem: "npm", Name: "react", Version: "18.2.0", Issue: Outdated, Owner: "web", Expires: "soon"},
+	}}
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	if err := baseline.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BASELINE_FILE", path)
+
+	tests := []struct {
+		name      string
+		ecosystem string
+		finding   Finding
+		accepted  []State
+		expired   int
+		status    string
+	}{
+		{"accepted issue", "npm", Finding{Name: "lodash", CurrentVersion: "4.17.20", Vulnerable: true}, []State{Vulnerable}, 0, "x 🔕 vulnerable accepted until " + tomorrow},
+		{"other issue of the same version", "npm", Finding{Name: "lodash", CurrentVersion: "4.17.20", UpdateNeeded: true}, nil, 0, "x"},
+		{"other version", "npm", Finding{Name: "lodash", CurrentVersion: "4.17.21", Vulnerable: true}, nil, 0, "x"},
+		{"other ecosystem", "cargo", Finding{Name: "lodash", CurrentVersion: "4.17.20", Vulnerable: true}, nil, 0, "x"},
+		{"expired entry", "npm", Finding{Name: "left-pad", CurrentVersion: "1.3.0", UpdateNeeded: true}, nil, 1, "x"},
+		{"entry without a valid expiry", "npm", Finding{Name: "react", CurrentVersion: "18.2.0", UpdateNeeded: true}, nil, 0, "x"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			report := NewReport("test", "target")
+			if len(report.Errors) != 1 {
+				t.Errorf("errors %q, want the entry without a valid expiry", report.Errors)
+			}
+
+			report.Add(Source{Path: "package.json", Ecosystem: test.ecosystem, Findings: []Finding{test.finding}})
+			// The tables render the findings as Report.Add marked them
+			finding := report.Sources[0].Findings[0]
+			if status := AcceptedStatus("x", finding); status != test.status {
+				t.Errorf("table status %q, want %q", status, test.status)
+			}
+			if len(finding.Accepted) != len(test.accepted) {
+				t.Fatalf("accepted %v, want %v", finding.Accepted, test.accepted)
+			}
+			for i, issue := range test.accepted {
+				if finding.Accepted[i].Issue != issue {
+					t.Errorf("accepted %s, want %s", finding.Accepted[i].Issue, issue)
+				}
+			}
+			if len(report.ExpiredBaseline) != test.expired {
+				t.Errorf("%d expired entries, want %d", len(report.ExpiredBaseline), test.expired)
+			}
+
+			report.Finish()
+			if report.Summary.Accepted != len(test.accepted) || report.Summary.Expired != test.expired {
+				t.Errorf("summary accepted %d, expired %d", report.Summary.Accepted, report.Summary.Expired)
+			}
+		})
+	}
+}
+
+func TestReportWithoutBaseline(t *testing.T) {
+	t.Setenv("BASELINE_FILE", filepath.Join(t.TempDir(), "missing.json"))
+	report := NewReport("test", "target")
+	report.Add(Source{Path: "package.json", Ecosystem: "npm", Findings: []Finding{{Name: "lodash", CurrentVersion: "4.17.20", Vulnerable: true}}})
+	report.Finish()
+	if report.Baseline != "" || len(report.Errors) != 0 || report.Summary.Vulnerable != 1 {
+		t.Errorf("baseline %q, errors %q, %d vulnerable", report.Baseline, report.Errors, report.Summary.Vulnerable)
+	}
+	if _, err := os.Stat(os.Getenv("BASELINE_FILE")); err == nil {
+		t.Error("a missing baseline was created")
+	}
+}
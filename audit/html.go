@@ -0,0 +1,304 @@
+package audit
+
+import (
+	"fmt"
+	"html/template"
+	"os"
+	"strings"
+)
+
+// --- HTML Dashboard ---
+
+// acceptedState marks dashboard rows whose issues the baseline all accepts; it is not a finding state
+const acceptedState State = "accepted"
+
+// stateOrder lists the states from most to least severe, for the chart and for sorting
+var stateOrder = []State{Vulnerable, Archived, Failed, Outdated, acceptedState, Unknown, UpToDate}
+
+// stateLabels are the badge texts and chart colours of each state
+var stateLabels = map[State]struct{ label, colour string }{
+	Vulnerable:    {"🚨 Vulnerable", "#d1242f"},
+	Archived:      {"⛔️ Archived", "#8250df"},
+	Failed:        {"❌ Error", "#6e7781"},
+	Outdated:      {"⬆️ Outdated", "#bf8700"},
+	acceptedState: {"🔕 Accepted", "#0969da"},
+	Unknown:       {"❔ Unknown", "#afb8c1"},
+	UpToDate:      {"✅ Up to date", "#1a7f37"},
+}
+
+// htmlRow is one dependency of the dashboard table
+type htmlRow struct {
+	Source    Source
+	Finding   Finding
+	State     State
+	Badge     string
+	Colour    string
+	Severity  int // Index in stateOrder, sorts the most severe first
+	Change    string
+	Location  string
+	Changelog string // Release notes and breaking changes as plain text, expanded on demand
+}
+
+// chartSegment is one bar of the status chart
+type chartSegment struct {
+	Label   string
+	Colour  string
+	Count   int
+	Percent float64
+}
+
+// htmlRows: Flattens the sources into table rows
+func htmlRows(r *Report) []htmlRow {
+	severity := make(map[State]int)
+	for i, state := range stateOrder {
+		severity[state] = i
+	}
+
+	var rows []htmlRow
+	for _, source := range r.Sources {
+		for _, finding := range source.Findings {
+			state := dashboardState(finding)
+			location := source.declaredIn(finding)
+			if finding.Line > 0 {
+				location = fmt.Sprintf("%s:%d", location, finding.Line)
+			}
+			change := ChangeLabel(finding.Bump, finding.ReleasesBehind)
+			if finding.CommitsBehind > 0 {
+				// Branch and commit pins are measured in commits, not releases
+				change = fmt.Sprintf("⏳ %d commits behind", finding.CommitsBehind)
+			}
+			rows = append(rows, htmlRow{
+				Source:    source,
+				Finding:   finding,
+				State:     state,
+				Badge:     stateLabels[state].label,
+				Colour:    stateLabels[state].colour,
+				Severity:  severity[state],
+				Change:    change,
+				Location:  location,
+				Changelog: plainChangelog(finding),
+			})
+		}
+	}
+	return rows
+}
+
+// dashboardState: The most severe issue of a finding that the baseline does not accept, as
+// Summarize counts them; a finding whose issues are all accepted shows as accepted
+func dashboardState(finding Finding) State {
+	switch {
+	case finding.Vulnerable && !finding.IsAccepted(Vulnerable):
+		return Vulnerable
+	case finding.Archived && !finding.IsAccepted(Archived):
+		return Archived
+	case finding.Error != "":
+		return Failed
+	case finding.UpdateNeeded && !finding.IsAccepted(Outdated):
+		return Outdated
+	case len(finding.Accepted) > 0:
+		return acceptedState
+	}
+	return finding.State()
+}
+
+// plainChangelog: Joins the advisories, breaking changes and release notes of a finding
+func plainChangelog(finding Finding) string {
+	var builder strings.Builder
+	for _, advisory := range finding.Advisories {
+		builder.WriteString("🚨 " + advisory + "\n")
+	}
+	for _, item := range finding.BreakingChanges {
+		text := item.Text
+		if item.URL != "" {
+			text = strings.TrimSpace(text + " " + item.URL)
+		}
+		builder.WriteString(fmt.Sprintf("%s %s: %s\n", item.Version, item.Kind.Label(), text))
+	}
+	if builder.Len() > 0 && len(finding.Changelog) > 0 {
+		builder.WriteString("\n")
+	}
+	for _, note := range finding.Changelog {
+		builder.WriteString("── " + note.Title())
+		if !note.Published.IsZero() {
+			builder.WriteString(" (" + note.Published.Format("2006-01-02") + ")")
+		}
+		builder.WriteString(" ──\n" + strings.TrimSpace(note.Body) + "\n\n")
+	}
+	return strings.TrimSpace(builder.String())
+}
+
+// chartSegments: Counts the findings per state; every finding is counted once, by its most severe
+// state the baseline does not accept
+func chartSegments(rows []htmlRow) []chartSegment {
+	counts := make(map[State]int)
+	for _, row := range rows {
+		counts[row.State]++
+	}
+	var segments []chartSegment
+	for _, state := range stateOrder {
+		if counts[state] == 0 {
+			continue
+		}
+		segments = append(segments, chartSegment{
+			Label:   stateLabels[state].label,
+			Colour:  stateLabels[state].colour,
+			Count:   counts[state],
+			Percent: 100 * float64(counts[state]) / float64(len(rows)),
+		})
+	}
+	return segments
+}
+
+// HTMLPath: The dashboard is written next to the Markdown report, e.g. cargo/report.md → cargo/report.html
+func HTMLPath(markdownFile string) string {
+	return strings.TrimSuffix(markdownFile, ".md") + ".html"
+}
+
+// WriteHTML writes the report as a single self-contained HTML page: no scripts, styles or fonts are loaded
+func (r *Report) WriteHTML(filename string) error {
+	rows := htmlRows(r)
+	data := struct {
+		Report   *Report
+		Rows     []htmlRow
+		Segments []chartSegment
+	}{r, rows, chartSegments(rows)}
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("error creating HTML report %s: %w", filename, err)
+	}
+	defer file.Close()
+
+	if err := dashboardTemplate.Execute(file, data); err != nil {
+		return fmt.Errorf("error writing HTML report %s: %w", filename, err)
+	}
+	return nil
+}
+
+var dashboardTemplate = template.Must(template.New("dashboard").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Dependency Dashboard · {{.Report.Command}} · {{.Report.Target}}</title>
+<style>
+  body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2rem; color: #1f2328; }
+  h1 { margin-bottom: .25rem; }
+  .meta { color: #656d76; margin-bottom: 1.5rem; }
+  .cards { display: flex; gap: 1rem; flex-wrap: wrap; margin-bottom: 1rem; }
+  .card { border: 1px solid #d0d7de; border-radius: 6px; padding: .75rem 1rem; min-width: 8rem; }
+  .card b { display: block; font-size: 1.6rem; }
+  .chart { display: flex; height: 1.5rem; border-radius: 6px; overflow: hidden; margin: 1rem 0 .5rem; }
+  .legend span { margin-right: 1rem; white-space: nowrap; }
+  .legend i { display: inline-block; width: .8rem; height: .8rem; border-radius: 2px; margin-right: .3rem; }
+  .controls { margin: 1.5rem 0 .75rem; display: flex; gap: .75rem; }
+  .controls input, .controls select { padding: .35rem .5rem; border: 1px solid #d0d7de; border-radius: 6px; }
+  .controls input { flex: 1; }
+  table { border-collapse: collapse; width: 100%; }
+  th, td { text-align: left; padding: .45rem .6rem; border-bottom: 1px solid #d0d7de; vertical-align: top; }
+  th { cursor: pointer; user-select: none; background: #f6f8fa; position: sticky; top: 0; }
+  th[data-dir="asc"]::after { content: " ▲"; }
+  th[data-dir="desc"]::after { content: " ▼"; }
+  code { font-size: .9em; }
+  .badge { display: inline-block; padding: .1rem .5rem; border-radius: 1rem; color: #fff; font-size: .85em; white-space: nowrap; }
+  details summary { cursor: pointer; color: #0969da; }
+  pre { white-space: pre-wrap; max-width: 60rem; max-height: 30rem; overflow: auto; background: #f6f8fa; padding: .75rem; border-radius: 6px; }
+  .muted { color: #656d76; }
+</style>
+</head>
+<body>
+<h1>📈 Dependency Dashboard</h1>
+<div class="meta"><code>{{.Report.Command}}</code> audit of <code>{{.Report.Target}}</code> · {{.Report.GeneratedAt.Format "2006-01-02 15:04 MST"}} · {{.Report.DurationMS}} ms · schema {{.Report.SchemaVersion}}</div>
+
+<div class="cards">
+  <div class="card"><b>{{.Report.Summary.Dependencies}}</b>Dependencies</div>
+  <div class="card"><b>{{.Report.Summary.UpToDate}}</b>✅ Up to date</div>
+  <div class="card"><b>{{.Report.Summary.Outdated}}</b>⬆️ Outdated</div>
+  <div class="card"><b>{{.Report.Summary.Major}}</b>🔴 Major updates</div>
+  <div class="card"><b>{{.Report.Summary.Vulnerable}}</b>🚨 Vulnerable</div>
+  <div class="card"><b>{{.Report.Summary.Archived}}</b>⛔️ Archived</div>
+  {{if .Report.Baseline}}<div class="card"><b>{{.Report.Summary.Accepted}}</b>🔕 Accepted</div>
+  <div class="card"><b>{{.Report.Summary.Expired}}</b>⏰ Expired</div>{{end}}
+</div>
+{{range .Report.ExpiredBaseline}}<p>⏰ <code>{{.Name}}</code> <code>{{.Version}}</code> ({{.Issue}}) was accepted by {{.Owner}} until {{.Expires}}: {{.Reason}}</p>{{end}}
+
+{{if .Segments}}
+<div class="chart" role="img" aria-label="Dependencies by status">
+  {{range .Segments}}<div style="width: {{printf "%.2f" .Percent}}%; background: {{.Colour}}" title="{{.Label}}: {{.Count}}"></div>{{end}}
+</div>
+<div class="legend">{{range .Segments}}<span><i style="background: {{.Colour}}"></i>{{.Label}}: <b>{{.Count}}</b> ({{printf "%.0f" .Percent}}%)</span>{{end}}</div>
+{{end}}
+
+{{range .Report.Errors}}<p>❌ {{.}}</p>{{end}}
+{{range .Report.Sources}}{{if .Error}}<p>❌ <code>{{.Path}}</code>: {{.Error}}</p>{{end}}{{end}}
+
+<div class="controls">
+  <input id="filter" type="search" placeholder="Filter by dependency, source or version…">
+  <select id="state">
+    <option value="">All statuses</option>
+    <option value="vulnerable">🚨 Vulnerable</option>
+    <option value="archived">⛔️ Archived</option>
+    <option value="error">❌ Error</option>
+    <option value="outdated">⬆️ Outdated</option>
+    <option value="unknown">❔ Unknown</option>
+    <option value="up-to-date">✅ Up to date</option>
+  </select>
+</div>
+
+<table id="dependencies">
+<thead><tr>
+  <th data-type="number">Severity</th><th>Dependency</th><th>Source</th><th>Change</th><th>Current</th><th>Latest</th><th data-type="number">Behind</th><th>Changelog</th>
+</tr></thead>
+<tbody>
+{{range .Rows}}<tr data-state="{{.State}}">
+  <td data-sort="{{.Severity}}"><span class="badge" style="background: {{.Colour}}">{{.Badge}}</span><div class="muted">{{.Finding.Status}}</div>{{range .Finding.Accepted}}<div class="muted" title="{{.Reason}}">🔕 {{.Issue}} accepted by {{.Owner}} until {{.Expires}}</div>{{end}}</td>
+  <td><code>{{.Finding.Name}}</code></td>
+  <td><code>{{.Location}}</code> <span class="muted">({{.Source.Ecosystem}})</span></td>
+  <td>{{.Change}}</td>
+  <td><code>{{.Finding.CurrentVersion}}</code></td>
+  <td><code>{{.Finding.LatestVersion}}</code></td>
+  <td data-sort="{{.Finding.ReleasesBehind}}">{{if .Finding.ReleasesBehind}}{{.Finding.ReleasesBehind}}{{end}}</td>
+  <td>{{if .Changelog}}<details><summary>{{len .Finding.Changelog}} release(s){{if .Finding.BreakingChanges}} · 💥 {{len .Finding.BreakingChanges}} breaking{{end}}</summary><pre>{{.Changelog}}</pre></details>{{else}}<span class="muted">-</span>{{end}}</td>
+</tr>
+{{end}}</tbody>
+</table>
+
+<script>
+(function () {
+  var table = document.getElementById("dependencies");
+  var body = table.tBodies[0];
+  var filter = document.getElementById("filter");
+  var state = document.getElementById("state");
+
+  function apply() {
+    var text = filter.value.toLowerCase();
+    Array.prototype.forEach.call(body.rows, function (row) {
+      var visible = (!state.value || row.dataset.state === state.value) &&
+        (!text || row.textContent.toLowerCase().indexOf(text) >= 0);
+      row.style.display = visible ? "" : "none";
+    });
+  }
+  filter.addEventListener("input", apply);
+  state.addEventListener("change", apply);
+
+  Array.prototype.forEach.call(table.tHead.rows[0].cells, function (header, column) {
+    header.addEventListener("click", function () {
+      var dir = header.dataset.dir === "asc" ? "desc" : "asc";
+      Array.prototype.forEach.call(table.tHead.rows[0].cells, function (cell) { delete cell.dataset.dir; });
+      header.dataset.dir = dir;
+      var numeric = header.dataset.type === "number";
+      var rows = Array.prototype.slice.call(body.rows);
+      rows.sort(function (a, b) {
+        var x = a.cells[column].dataset.sort || a.cells[column].textContent.trim();
+        var y = b.cells[column].dataset.sort || b.cells[column].textContent.trim();
+        var order = numeric ? (Number(x) || 0) - (Number(y) || 0) : x.localeCompare(y, undefined, {numeric: true});
+        return dir === "asc" ? order : -order;
+      });
+      rows.forEach(function (row) { body.appendChild(row); });
+    });
+  });
+})();
+</script>
+</body>
+</html>
+`))
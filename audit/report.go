@@ -0,0 +1,275 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// SchemaVersion is the version of the JSON report layout. The minor version grows with new
+// fields; the major version changes only when fields are removed or change meaning.
+const SchemaVersion = "1.3"
+
+// --- Status ---
+
+// State is the machine-readable status of a finding
+type State string
+
+const (
+	UpToDate   State = "up-to-date"
+	Outdated   State = "outdated"
+	Vulnerable State = "vulnerable"
+	Archived   State = "archived"
+	Failed     State = "error"
+	Unknown    State = "unknown" // Nothing to compare against, e.g. branch pins or local paths
+)
+
+// State: Derives the status from the flags the auditor set, most severe first
+func (f Finding) State() State {
+	switch {
+	case f.Vulnerable:
+		return Vulnerable
+	case f.Archived:
+		return Archived
+	case f.Error != "":
+		return Failed
+	case f.UpdateNeeded:
+		return Outdated
+	case f.Compared:
+		return UpToDate
+	}
+	return Unknown
+}
+
+// MarshalJSON adds the derived "status" to the finding's fields
+func (f Finding) MarshalJSON() ([]byte, error) {
+	type plainFinding Finding
+	return json.Marshal(struct {
+		State State `json:"status"`
+		plainFinding
+	}{f.State(), plainFinding(f)})
+}
+
+// --- Report Model ---
+
+// Source is one audited manifest, binary, image or repository
+type Source struct {
+	Path       string    `json:"path"`
+	Ecosystem  string    `json:"ecosystem"`
+	DurationMS int64     `json:"durationMs"`
+	Findings   []Finding `json:"dependencies"`
+	Error      string    `json:"error,omitempty"`      // The source could not be audited at all
+	Repository string    `json:"repository,omitempty"` // Repository a fleet scan read the source from (owner/repo); Path starts with it
+}
+
+// declaredIn: The manifest a finding of the source is declared in
+func (s Source) declaredIn(finding Finding) string {
+	if finding.Manifest != "" {
+		return finding.Manifest
+	}
+	return s.Path
+}
+
+// NewSource: Records the outcome of auditing one source that started at started, and looks up
+// where in the source each finding is declared
+func NewSource(path, ecosystem string, started time.Time, findings []Finding, err error) Source {
+	locateDeclarations(path, ecosystem, findings)
+	source := Source{Path: path, Ecosystem: ecosystem, DurationMS: time.Since(started).Milliseconds(), Findings: findings}
+	if err != nil {
+		source.Error = err.Error()
+	}
+	return source
+}
+
+// Summary counts the findings of a report by status
+type Summary struct {
+	Dependencies int `json:"dependencies"`
+	UpToDate     int `json:"upToDate"`
+	Outdated     int `json:"outdated"`
+	Major        int `json:"majorUpdates"`
+	Vulnerable   int `json:"vulnerable"`
+	Archived     int `json:"archived"`
+	Errors       int `json:"errors"`
+	Unknown      int `json:"unknown"`
+	Accepted     int `json:"accepted"` // Issues left out of the counts above by the baseline
+	Expired      int `json:"expired"`  // Baseline entries past their expiry date; their issues are counted again
+}
+
+// Summarize counts findings by status. The counts overlap: an archived dependency with an
+// available update counts as both archived and outdated. Issues accepted by the baseline are not counted.
+func Summarize(findings []Finding) Summary {
+	summary := Summary{Dependencies: len(findings)}
+	for _, finding := range findings {
+		switch finding.State() {
+		case UpToDate:
+			summary.UpToDate++
+		case Failed:
+			summary.Errors++
+		case Unknown:
+			summary.Unknown++
+		}
+		summary.Accepted += len(finding.Accepted)
+		if finding.UpdateNeeded && !finding.IsAccepted(Outdated) {
+			summary.Outdated++
+		}
+		if finding.Bump.Breaking() && !finding.IsAccepted(Outdated) {
+			summary.Major++
+		}
+		if finding.Vulnerable && !finding.IsAccepted(Vulnerable) {
+			summary.Vulnerable++
+		}
+		if finding.Archived && !finding.IsAccepted(Archived) {
+			summary.Archived++
+		}
+	}
+	return summary
+}
+
+// Report is the result of one command, the model both the JSON and the Markdown output are written from
+type Report struct {
+	SchemaVersion string    `json:"schemaVersion"`
+	Command       string    `json:"command"` // "frontend", "scan", "fleet", ...
+	Target        string    `json:"target"`  // What was audited: a manifest, directory, organisation, ...
+	GeneratedAt   time.Time `json:"generatedAt"`
+	DurationMS    int64     `json:"durationMs"`
+	Summary       Summary   `json:"summary"`
+	Sources       []Source  `json:"sources"`
+	Errors        []string  `json:"errors,omitempty"` // Problems not tied to a single source
+
+	Baseline        string          `json:"baseline,omitempty"`        // The baseline file applied, if any
+	ExpiredBaseline []BaselineEntry `json:"expiredBaseline,omitempty"` // Accepted issues that are reported again
+	started         time.Time
+	baseline        *Baseline
+}
+
+// NewReport: Starts the report of a command and reads the baseline in use; the duration runs until Finish
+func NewReport(command, target string) *Report {
+	now := time.Now()
+	report := &Report{SchemaVersion: SchemaVersion, Command: command, Target: target, GeneratedAt: now.UTC(), Sources: []Source{}, started: now}
+	report.loadBaseline()
+	return report
+}
+
+// Add: Records an audited source and marks the issues the baseline accepts, before anything is rendered
+func (r *Report) Add(source Source) {
+	if r.baseline != nil {
+		r.ExpiredBaseline = append(r.ExpiredBaseline, r.baseline.apply(source.Ecosystem, source.Findings, time.Now())...)
+	}
+	r.Sources = append(r.Sources, source)
+}
+
+// Findings lists the findings of every source
+func (r *Report) Findings() []Finding {
+	var findings []Finding
+	for _, source := range r.Sources {
+		findings = append(findings, source.Findings...)
+	}
+	return findings
+}
+
+// SourceFindings lists the findings of the source read from path
+func (r *Report) SourceFindings(path string) []Finding {
+	for _, source := range r.Sources {
+		if source.Path == path {
+			return source.Findings
+		}
+	}
+	return nil
+}
+
+// Finish: Stops the clock and computes the summary
+func (r *Report) Finish() {
+	r.DurationMS = time.Since(r.started).Milliseconds()
+	r.Summary = Summarize(r.Findings())
+	r.Summary.Expired = len(r.ExpiredBaseline)
+}
+
+// --- Output ---
+
+// JSONPath: The JSON report is written next to the Markdown one, e.g. cargo/report.md → cargo/report.json
+func JSONPath(markdownFile string) string {
+	return strings.TrimSuffix(markdownFile, ".md") + ".json"
+}
+
+// SARIFPath: The SARIF log is written next to the Markdown report, e.g. cargo/report.md → cargo/report.sarif
+func SARIFPath(markdownFile string) string {
+	return strings.TrimSuffix(markdownFile, ".md") + ".sarif"
+}
+
+// OutputFiles names the files the report called name writes for markdownFile, for its completion message
+func OutputFiles(name, markdownFile string) string {
+	files := append([]string{markdownFile}, TemplateOutputs(name, markdownFile)...)
+	files = append(files, HTMLPath(markdownFile), JSONPath(markdownFile), SARIFPath(markdownFile))
+	return FileList(files)
+}
+
+// FileList: Names files for a completion message, e.g. "**a.md**, **a.html** and **a.json**"
+func FileList(files []string) string {
+	quoted := make([]string, len(files))
+	for i, file := range files {
+		quoted[i] = "**" + file + "**"
+	}
+	if len(quoted) < 2 {
+		return strings.Join(quoted, "")
+	}
+	return strings.Join(quoted[:len(quoted)-1], ", ") + " and " + quoted[len(quoted)-1]
+}
+
+// WriteFiles writes the HTML dashboard and the machine-readable reports (JSON and SARIF) next to markdownFile
+func (r *Report) WriteFiles(markdownFile string) error {
+	if err := r.WriteHTML(HTMLPath(markdownFile)); err != nil {
+		return err
+	}
+	if err := r.WriteJSON(JSONPath(markdownFile)); err != nil {
+		return err
+	}
+	if err := r.writeHistory(); err != nil {
+		return err
+	}
+	return r.WriteSARIF(SARIFPath(markdownFile))
+}
+
+// writeHistory: Keeps a timestamped copy of the JSON report in REPORT_HISTORY, if set, so later
+// runs can be compared with `sbom diff`, e.g. history/scan-20241018T120000Z.json
+func (r *Report) writeHistory() error {
+	dir := os.Getenv("REPORT_HISTORY")
+	if dir == "" {
+		return nil
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("error creating report history %s: %w", dir, err)
+	}
+	name := fmt.Sprintf("%s-%s.json", r.Command, r.GeneratedAt.UTC().Format("20060102T150405Z"))
+	return r.WriteJSON(filepath.Join(dir, name))
+}
+
+// WriteJSON writes the report as indented JSON
+func (r *Report) WriteJSON(filename string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding JSON report: %w", err)
+	}
+	if err := os.WriteFile(filename, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("error writing JSON report %s: %w", filename, err)
+	}
+	return nil
+}
+
+// WriteSummary writes the summary as a Markdown list
+func (r *Report) WriteSummary(writer *bufio.Writer) {
+	summary := r.Summary
+	_, _ = writer.WriteString(fmt.Sprintf("* 📦 Dependencies: **%d** (✅ %d up to date, ⬆️ %d outdated, 🔴 %d major)\n",
+		summary.Dependencies, summary.UpToDate, summary.Outdated, summary.Major))
+	_, _ = writer.WriteString(fmt.Sprintf("* 🚨 Vulnerable: **%d** · ⛔️ Archived: **%d** · ❌ Errors: **%d**\n", summary.Vulnerable, summary.Archived, summary.Errors))
+	if r.Baseline != "" {
+		_, _ = writer.WriteString(fmt.Sprintf("* 🔕 Accepted in `%s`: **%d** · ⏰ Expired: **%d**\n", r.Baseline, summary.Accepted, summary.Expired))
+		for _, entry := range r.ExpiredBaseline {
+			_, _ = writer.WriteString(fmt.Sprintf("  * ⏰ `%s` `%s` (%s), accepted by %s until %s: %s\n", entry.Name, entry.Version, entry.Issue, entry.Owner, entry.Expires, entry.Reason))
+		}
+	}
+	_, _ = writer.WriteString(fmt.Sprintf("* ⏱️ Audited in %s (schema %s)\n\n", (time.Duration(r.DurationMS) * time.Millisecond).Round(time.Millisecond), r.SchemaVersion))
+}
@@ -0,0 +1,58 @@
+package audit
+
+import "testing"
+
+func TestFindingState(t *testing.T) {
+	tests := []struct {
+		name    string
+		finding Finding
+		want    State
+	}{
+		{"compared, current", Finding{Compared: true, Status: "Up to date with main"}, UpToDate},
+		{"compared, behind", Finding{Compared: true, UpdateNeeded: true}, Outdated},
+		{"not compared", Finding{Status: "✅ Tracks main"}, Unknown},
+		{"error", Finding{Error: "registry unreachable", Status: "Could not be checked"}, Failed},
+		{"error text alone", Finding{Compared: true, Status: "❌ looks like an error"}, UpToDate},
+		{"archived over error", Finding{Archived: true, Error: "no releases"}, Archived},
+		{"vulnerable over archived", Finding{Vulnerable: true, Archived: true, UpdateNeeded: true}, Vulnerable},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.finding.State(); got != test.want {
+				t.Errorf("got %s, want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestDashboardState(t *testing.T) {
+	accept := func(issues ...State) []BaselineEntry {
+		var entries []BaselineEntry
+		for _, issue := range issues {
+			entries = append(entries, BaselineEntry{Issue: issue, Expires: "2099-01-01"})
+		}
+		return entries
+	}
+	tests := []struct {
+		name    string
+		finding Finding
+		want    State
+	}{
+		{"vulnerable", Finding{Vulnerable: true, UpdateNeeded: true, Compared: true}, Vulnerable},
+		{"vulnerable accepted", Finding{Vulnerable: true, Compared: true, Accepted: accept(Vulnerable)}, acceptedState},
+		{"vulnerable accepted, still outdated", Finding{Vulnerable: true, UpdateNeeded: true, Compared: true, Accepted: accept(Vulnerable)}, Outdated},
+		{"vulnerable and outdated accepted", Finding{Vulnerable: true, UpdateNeeded: true, Compared: true, Accepted: accept(Vulnerable, Outdated)}, acceptedState},
+		{"archived accepted, vulnerable", Finding{Vulnerable: true, Archived: true, Accepted: accept(Archived)}, Vulnerable},
+		{"archived accepted, error", Finding{Archived: true, Error: "no releases", Accepted: accept(Archived)}, Failed},
+		{"outdated accepted", Finding{UpdateNeeded: true, Compared: true, Accepted: accept(Outdated)}, acceptedState},
+		{"up to date", Finding{Compared: true}, UpToDate},
+		{"unknown", Finding{}, Unknown},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := dashboardState(test.finding); got != test.want {
+				t.Errorf("got %s, want %s", got, test.want)
+			}
+		})
+	}
+}
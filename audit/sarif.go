@@ -0,0 +1,257 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// --- Declaration Lines ---
+
+// maxManifestSize bounds the files searched for declarations; binaries and image archives are skipped
+const maxManifestSize = 4 << 20
+
+// declarationPatterns: How each ecosystem's manifest spells the declaration of a dependency
+func declarationPatterns(ecosystem, name string) []*regexp.Regexp {
+	quoted := regexp.QuoteMeta(name)
+	switch ecosystem {
+	case "npm":
+		return []*regexp.Regexp{regexp.MustCompile(`"` + quoted + `"\s*:`)}
+	case "rebar":
+		return []*regexp.Regexp{regexp.MustCompile(`\{\s*` + quoted + `\s*,`)}
+	case "cargo":
+		return []*regexp.Regexp{regexp.MustCompile(`^\s*` + quoted + `\s*[=.]`), regexp.MustCompile(`"` + quoted + `"`)}
+	case "maven":
+		_, artifact, _ := strings.Cut(name, ":")
+		return []*regexp.Regexp{
+			regexp.MustCompile(`<artifactId>\s*` + regexp.QuoteMeta(artifact) + `\s*</artifactId>`),
+			regexp.MustCompile(quoted),
+			regexp.MustCompile(`name\s*=\s*"` + regexp.QuoteMeta(artifact) + `"`),
+		}
+	case "docker":
+		image := regexp.QuoteMeta(strings.TrimPrefix(name, "library/"))
+		return []*regexp.Regexp{regexp.MustCompile(`(?i)^\s*(FROM|image:)(.*[\s/"'])?` + image + `([:@\s"']|$)`)}
+	case "github-actions":
+		return []*regexp.Regexp{regexp.MustCompile(`uses:\s*["']?` + quoted + `[/@]`)}
+	}
+	return []*regexp.Regexp{regexp.MustCompile(`(^|[\s"'{(/=])` + quoted + `($|[\s"',@:)}])`)}
+}
+
+// readManifestLines: The lines of path, or nil when it is not a readable text manifest
+func readManifestLines(path string) []string {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() || info.Size() > maxManifestSize {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || strings.ContainsRune(string(data), 0) {
+		return nil
+	}
+	return strings.Split(string(data), "\n")
+}
+
+// locateDeclarations: Sets the line each finding is declared on, searching the finding's own
+// manifest (e.g. a workspace member) or else path, when it is a readable text manifest
+func locateDeclarations(path, ecosystem string, findings []Finding) {
+	files := make(map[string][]string)
+	for i := range findings {
+		if findings[i].Line > 0 {
+			continue
+		}
+		manifest := findings[i].Manifest
+		if manifest == "" {
+			manifest = path
+		}
+		lines, ok := files[manifest]
+		if !ok {
+			lines = readManifestLines(manifest)
+			files[manifest] = lines
+		}
+	patterns:
+		for _, pattern := range declarationPatterns(ecosystem, findings[i].Name) {
+			for number, line := range lines {
+				if pattern.MatchString(line) {
+					findings[i].Line = number + 1
+					break patterns
+				}
+			}
+		}
+	}
+}
+
+// --- SARIF 2.1.0 ---
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri,omitempty"`
+	Version        string      `json:"version"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string       `json:"id"`
+	Name                 string       `json:"name"`
+	ShortDescription     sarifMessage `json:"shortDescription"`
+	DefaultConfiguration struct {
+		Level string `json:"level"`
+	} `json:"defaultConfiguration"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID              string             `json:"ruleId"`
+	Level               string             `json:"level"`
+	Message             sarifMessage       `json:"message"`
+	Locations           []sarifLocation    `json:"locations"`
+	PartialFingerprints map[string]string  `json:"partialFingerprints"`
+	Properties          map[string]any     `json:"properties,omitempty"`
+	Suppressions        []sarifSuppression `json:"suppressions,omitempty"` // Set for issues accepted by the baseline
+}
+
+type sarifSuppression struct {
+	Kind          string `json:"kind"`
+	Status        string `json:"status"`
+	Justification string `json:"justification"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation struct {
+		ArtifactLocation struct {
+			URI       string `json:"uri"`
+			URIBaseID string `json:"uriBaseId,omitempty"`
+		} `json:"artifactLocation"`
+		Region *sarifRegion `json:"region,omitempty"`
+	} `json:"physicalLocation"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// sarifRules are the three kinds of result; the level of an outdated dependency depends on the bump
+var sarifRules = []struct {
+	id, name, description, level string
+}{
+	{"SBOM001", "VulnerableDependency", "A newer release fixes a security issue, or the version in use was yanked", "error"},
+	{"SBOM002", "ArchivedDependency", "The upstream repository of the dependency is archived", "warning"},
+	{"SBOM003", "OutdatedDependency", "A newer version of the dependency is available", "note"},
+}
+
+// sarifResults: One result per vulnerable, archived or outdated finding, most severe rule first
+func sarifResults(source Source, finding Finding) []sarifResult {
+	var results []sarifResult
+	add := func(ruleID string, issue State, level, text string) {
+		result := sarifResult{
+			RuleID:  ruleID,
+			Level:   level,
+			Message: sarifMessage{Text: text},
+			PartialFingerprints: map[string]string{
+				"dependency/v1": fmt.Sprintf("%s:%s:%s", source.Ecosystem, finding.Name, ruleID),
+			},
+			Properties: map[string]any{
+				"ecosystem":      source.Ecosystem,
+				"currentVersion": finding.CurrentVersion,
+				"latestVersion":  finding.LatestVersion,
+			},
+		}
+		if finding.Bump != "" {
+			result.Properties["bump"] = finding.Bump
+			result.Properties["releasesBehind"] = finding.ReleasesBehind
+		}
+
+		var location sarifLocation
+		manifest := source.declaredIn(finding)
+		location.PhysicalLocation.ArtifactLocation.URI = filepath.ToSlash(filepath.Clean(manifest))
+		// Fleet paths start with owner/repo, which no single %SRCROOT% checkout resolves
+		if !filepath.IsAbs(manifest) && source.Repository == "" {
+			location.PhysicalLocation.ArtifactLocation.URIBaseID = "%SRCROOT%"
+		}
+		if finding.Line > 0 {
+			location.PhysicalLocation.Region = &sarifRegion{StartLine: finding.Line}
+		}
+		result.Locations = []sarifLocation{location}
+
+		for _, entry := range finding.Accepted {
+			if entry.Issue == issue {
+				justification := fmt.Sprintf("%s (owner: %s, until %s)", entry.Reason, entry.Owner, entry.Expires)
+				result.Suppressions = append(result.Suppressions, sarifSuppression{Kind: "external", Status: "accepted", Justification: justification})
+			}
+		}
+		results = append(results, result)
+	}
+
+	if finding.Vulnerable {
+		text := fmt.Sprintf("%s %s has a security fix or was yanked; upgrade to %s.", finding.Name, finding.CurrentVersion, finding.LatestVersion)
+		if len(finding.Advisories) > 0 {
+			text += " " + strings.Join(finding.Advisories, "; ") + "."
+		}
+		add("SBOM001", Vulnerable, "error", text)
+	}
+	if finding.Archived {
+		add("SBOM002", Archived, "warning", fmt.Sprintf("The upstream repository of %s is archived and no longer maintained.", finding.Name))
+	}
+	if finding.UpdateNeeded && !finding.Vulnerable {
+		level := "note"
+		if finding.Bump.Breaking() {
+			level = "warning"
+		}
+		change := ""
+		if finding.Bump != "" {
+			change = fmt.Sprintf(" (%s update)", finding.Bump)
+		}
+		add("SBOM003", Outdated, level, fmt.Sprintf("%s can be updated from %s to %s%s.", finding.Name, finding.CurrentVersion, finding.LatestVersion, change))
+	}
+	return results
+}
+
+// WriteSARIF writes the report as a SARIF 2.1.0 log for code-scanning integrations
+func (r *Report) WriteSARIF(filename string) error {
+	driver := sarifDriver{Name: "sbom", Version: SchemaVersion}
+	for _, rule := range sarifRules {
+		sarif := sarifRule{ID: rule.id, Name: rule.name, ShortDescription: sarifMessage{Text: rule.description}}
+		sarif.DefaultConfiguration.Level = rule.level
+		driver.Rules = append(driver.Rules, sarif)
+	}
+
+	run := sarifRun{Tool: sarifTool{Driver: driver}, Results: []sarifResult{}}
+	for _, source := range r.Sources {
+		for _, finding := range source.Findings {
+			run.Results = append(run.Results, sarifResults(source, finding)...)
+		}
+	}
+
+	log := sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	}
+	data, err := json.MarshalIndent(log, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding SARIF report: %w", err)
+	}
+	if err := os.WriteFile(filename, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("error writing SARIF report %s: %w", filename, err)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLocateDeclarations(t *testing.T) {
+	dir := t.TempDir()
+	root := filepath.Join(dir, "Cargo.toml")
+	member := filepath.Join(dir, "crates", "cli", "Cargo.toml")
+	if err := os.MkdirAll(filepath.Dir(member), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(root, []byte("[workspace]\nmembers = [\"crates/*\"]\n\n[workspace.dependencies]\nserde = \"1\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(member, []byte("[package]\nname = \"cli\"\n\n[dependencies]\nclap = \"4\"\nserde.workspace = true\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		finding Finding
+		want    int
+	}{
+		{"root manifest", Finding{Name: "serde"}, 5},
+		{"member manifest", Finding{Name: "clap", Manifest: member}, 5},
+		{"inherited in a member", Finding{Name: "serde", Manifest: member}, 6},
+		{"not declared", Finding{Name: "tokio", Manifest: member}, 0},
+		{"unreadable member", Finding{Name: "clap", Manifest: filepath.Join(dir, "missing", "Cargo.toml")}, 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			findings := []Finding{test.finding}
+			locateDeclarations(root, "cargo", findings)
+			if findings[0].Line != test.want {
+				t.Errorf("got line %d, want %d", findings[0].Line, test.want)
+			}
+		})
+	}
+}
+
+func TestSARIFLocation(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  Source
+		finding Finding
+		uri     string
+		baseID  string
+	}{
+		{"local manifest", Source{Path: "cargo/Cargo.toml"}, Finding{}, "cargo/Cargo.toml", "%SRCROOT%"},
+		{"absolute manifest", Source{Path: "/srv/app/package.json"}, Finding{}, "/srv/app/package.json", ""},
+		{"workspace member", Source{Path: "Cargo.toml"}, Finding{Manifest: "crates/cli/Cargo.toml"}, "crates/cli/Cargo.toml", "%SRCROOT%"},
+		{"fleet manifest", Source{Path: "acme/api/go.mod", Repository: "acme/api"}, Finding{}, "acme/api/go.mod", ""},
+		{"fleet workspace member", Source{Path: "acme/api/Cargo.toml", Repository: "acme/api"}, Finding{Manifest: "acme/api/crates/cli/Cargo.toml"}, "acme/api/crates/cli/Cargo.toml", ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.finding.Name = "lib"
+			test.finding.Compared = true
+			test.finding.UpdateNeeded = true
+			results := sarifResults(test.source, test.finding)
+			if len(results) == 0 {
+				t.Fatal("no SARIF result")
+			}
+			artifact := results[0].Locations[0].PhysicalLocation.ArtifactLocation
+			if artifact.URI != test.uri || artifact.URIBaseID != test.baseID {
+				t.Errorf("got %q (base %q), want %q (base %q)", artifact.URI, artifact.URIBaseID, test.uri, test.baseID)
+			}
+		})
+	}
+}
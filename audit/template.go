@@ -0,0 +1,198 @@
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"Sbom/changelog"
+)
+
+// --- Report Templates ---
+
+// TemplateData is the model every report template receives
+type TemplateData struct {
+	Report  *Report     // Ecosystem-neutral sources, findings, summary and errors, as in the JSON report
+	Results interface{} // The command's own results, e.g. the []cargo.CrateDependency behind the default table
+}
+
+// Template renders a command's report. Text is the built-in Markdown template; REPORT_TEMPLATES
+// (comma-separated "report=path" entries, e.g. "cargo=cargo.md.tmpl,fleet=fleet.csv.tmpl"; a bare
+// path applies to every report) adds user templates that receive the same TemplateData:
+//   - a template named "*.md.tmpl" replaces the built-in Markdown report,
+//   - any other "name.ext.tmpl" is written next to it, e.g. cargo/report.md → cargo/report.name.ext.
+//
+// Templates ending in .html(.tmpl) or .htm(.tmpl) are parsed with html/template, all others with text/template.
+// A failing user template is reported as a warning; the built-in Markdown report is written regardless.
+type Template struct {
+	Name  string                 // Names the report in REPORT_TEMPLATES and errors, e.g. "cargo"
+	Text  string                 // The built-in Markdown template
+	Funcs map[string]interface{} // Helpers of the command, e.g. its "table" writer; added to templateFuncs
+}
+
+// templateFuncs are available to every template
+var templateFuncs = map[string]interface{}{
+	"summary":      func(r *Report) string { return Capture(r.WriteSummary) },
+	"changeLabel":  ChangeLabel,
+	"updateStatus": UpdateStatus,
+	"accepted":     AcceptedStatus,
+	"breaking":     changelog.FormatBreaking,
+	"consolidate":  changelog.Consolidate,
+	"add":          func(a, b int) int { return a + b },
+	"join":         strings.Join,
+	"lower":        strings.ToLower,
+	"upper":        strings.ToUpper,
+	"trimV":        func(version string) string { return strings.TrimPrefix(version, "v") },
+	"date":         func(t time.Time) string { return t.Format("2006-01-02") },
+	"code":         func(value interface{}) string { return "`" + fmt.Sprint(value) + "`" },
+	"cell":         markdownCell,
+	"csv":          csvField,
+}
+
+// Capture: Runs a Markdown writer into a string, so templates can embed the shared table writers
+func Capture(write func(*bufio.Writer)) string {
+	var buffer bytes.Buffer
+	writer := bufio.NewWriter(&buffer)
+	write(writer)
+	_ = writer.Flush()
+	return buffer.String()
+}
+
+// TableWriter writes the table of one audited manifest. It renders the findings of the manifest's
+// source as Report.Add left them, baseline applied; the auditor's own results only add detail.
+type TableWriter func(writer *bufio.Writer, findings []Finding)
+
+// markdownCell: Flattens a value into one Markdown table cell
+func markdownCell(value string) string {
+	value = strings.ReplaceAll(strings.TrimSpace(value), "\r\n", "\n")
+	return strings.ReplaceAll(strings.ReplaceAll(value, "|", "\\|"), "\n", "<br>")
+}
+
+// csvField: Quotes a value for a CSV column when it contains a separator, quote or line break
+func csvField(value string) string {
+	if !strings.ContainsAny(value, ",\"\r\n") {
+		return value
+	}
+	return `"` + strings.ReplaceAll(value, `"`, `""`) + `"`
+}
+
+// userTemplates: Reads the REPORT_TEMPLATES entries of the report called name
+func userTemplates(name string) []string {
+	var paths []string
+	for _, entry := range strings.Split(os.Getenv("REPORT_TEMPLATES"), ",") {
+		path := strings.TrimSpace(entry)
+		if report, scoped, ok := strings.Cut(path, "="); ok {
+			if strings.TrimSpace(report) != name {
+				continue
+			}
+			path = strings.TrimSpace(scoped)
+		}
+		if path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// outputName: Strips the template suffix off a template file name, e.g. "confluence.html.tmpl" → "confluence.html"
+func outputName(templatePath string) string {
+	name := filepath.Base(templatePath)
+	for _, suffix := range []string{".tmpl", ".gotmpl", ".tpl"} {
+		name = strings.TrimSuffix(name, suffix)
+	}
+	return name
+}
+
+// templateOutput: The file a user template renders to; the .md template replaces the Markdown report itself
+func templateOutput(markdownFile, templatePath string) string {
+	name := outputName(templatePath)
+	if filepath.Ext(name) == ".md" {
+		return markdownFile
+	}
+	return strings.TrimSuffix(markdownFile, ".md") + "." + name
+}
+
+// TemplateOutputs lists the additional files REPORT_TEMPLATES writes next to markdownFile for the report called name
+func TemplateOutputs(name, markdownFile string) []string {
+	var outputs []string
+	for _, path := range userTemplates(name) {
+		if output := templateOutput(markdownFile, path); output != markdownFile {
+			outputs = append(outputs, output)
+		}
+	}
+	return outputs
+}
+
+// executor is what text/template and html/template templates have in common
+type executor interface {
+	Execute(writer io.Writer, data interface{}) error
+}
+
+// parseUserTemplate: Parses a template file with the package matching its output format
+func (t Template) parseUserTemplate(path string) (executor, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading report template: %w", err)
+	}
+
+	name := filepath.Base(path)
+	if ext := filepath.Ext(outputName(path)); ext == ".html" || ext == ".htm" {
+		parsed, err := htmltemplate.New(name).Funcs(templateFuncs).Funcs(t.Funcs).Parse(string(content))
+		if err != nil {
+			return nil, fmt.Errorf("error parsing report template %s: %w", path, err)
+		}
+		return parsed, nil
+	}
+	parsed, err := template.New(name).Funcs(templateFuncs).Funcs(t.Funcs).Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing report template %s: %w", path, err)
+	}
+	return parsed, nil
+}
+
+// Write renders the Markdown report to markdownFile, then every user template next to it. A user
+// template that cannot be read, parsed or rendered is skipped with a warning; if it was to replace
+// the Markdown report, the built-in one is written instead.
+func (t Template) Write(markdownFile string, data TemplateData) error {
+	builtIn, err := template.New(t.Name).Funcs(templateFuncs).Funcs(t.Funcs).Parse(t.Text)
+	if err != nil {
+		return fmt.Errorf("error parsing the %s report template: %w", t.Name, err)
+	}
+
+	replaced := false
+	for _, path := range userTemplates(t.Name) {
+		filename := templateOutput(markdownFile, path)
+		parsed, err := t.parseUserTemplate(path)
+		if err == nil {
+			err = writeTemplate(filename, parsed, data)
+		}
+		if err != nil {
+			fmt.Printf("⚠️ Warning: Skipping report template: %v\n", err)
+			continue
+		}
+		replaced = replaced || filename == markdownFile
+	}
+	if replaced {
+		return nil
+	}
+	return writeTemplate(markdownFile, builtIn, data)
+}
+
+// writeTemplate: Renders into memory first, so a failing template leaves no half-written report
+func writeTemplate(filename string, tmpl executor, data TemplateData) error {
+	var buffer bytes.Buffer
+	if err := tmpl.Execute(&buffer, data); err != nil {
+		return fmt.Errorf("error rendering report %s: %w", filename, err)
+	}
+	if err := os.WriteFile(filename, buffer.Bytes(), 0644); err != nil {
+		return fmt.Errorf("could not create report file %s: %w", filename, err)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUserTemplates(t *testing.T) {
+	tests := []struct {
+		name      string
+		templates string
+		report    string
+		want      []string
+	}{
+		{"unset", "", "cargo", nil},
+		{"bare path applies to every report", "all.csv.tmpl", "cargo", []string{"all.csv.tmpl"}},
+		{"scoped to the report", "cargo=cargo.md.tmpl, fleet=fleet.csv.tmpl", "cargo", []string{"cargo.md.tmpl"}},
+		{"scoped to another report", "fleet=fleet.md.tmpl", "cargo", nil},
+		{"mixed", "all.csv.tmpl,fleet = fleet.md.tmpl,,cargo=", "fleet", []string{"all.csv.tmpl", "fleet.md.tmpl"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv("REPORT_TEMPLATES", test.templates)
+			got := userTemplates(test.report)
+			if strings.Join(got, ",") != strings.Join(test.want, ",") {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestTemplateWrite(t *testing.T) {
+	dir := t.TempDir()
+	templates := map[string]string{
+		"report.md.tmpl": "user {{.Results}}",
+		"broken.md.tmpl": "{{.Missing.Field}}",
+		"parse.csv.tmpl": "{{if}}",
+		"list.csv.tmpl":  "{{.Results}},csv",
+	}
+	for name, text := range templates {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(text), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	path := func(name string) string { return filepath.Join(dir, name) }
+
+	tests := []struct {
+		name      string
+		templates string
+		markdown  string
+		extra     map[string]string // Additional outputs by suffix, "" when they must not exist
+	}{
+		{"built-in", "", "built-in x", nil},
+		{"replaced", "cargo=" + path("report.md.tmpl"), "user x", nil},
+		{"other report's template", "fleet=" + path("report.md.tmpl"), "built-in x", nil},
+		{"failing replacement falls back", "cargo=" + path("broken.md.tmpl"), "built-in x", nil},
+		{"missing template", "cargo=" + path("missing.md.tmpl"), "built-in x", nil},
+		{
+			"unparsable template next to a working one",
+			"cargo=" + path("parse.csv.tmpl") + ",cargo=" + path("list.csv.tmpl"),
+			"built-in x",
+			map[string]string{".parse.csv": "", ".list.csv": "x,csv"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv("REPORT_TEMPLATES", test.templates)
+			out := t.TempDir()
+			markdownFile := filepath.Join(out, "report.md")
+			tmpl := Template{Name: "cargo", Text: "built-in {{.Results}}"}
+			if err := tmpl.Write(markdownFile, TemplateData{Results: "x"}); err != nil {
+				t.Fatal(err)
+			}
+			if got, _ := os.ReadFile(markdownFile); string(got) != test.markdown {
+				t.Errorf("Markdown report %q, want %q", got, test.markdown)
+			}
+			for suffix, want := range test.extra {
+				got, err := os.ReadFile(filepath.Join(out, "report"+suffix))
+				if want == "" {
+					if err == nil {
+						t.Errorf("report%s written by a failing template", suffix)
+					}
+					continue
+				}
+				if string(got) != want {
+					t.Errorf("report%s %q, want %q", suffix, got, want)
+				}
+			}
+		})
+	}
+}
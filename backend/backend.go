@@ -1,16 +1,17 @@
-package main
+// Package backend audits the git/tag dependencies of an Erlang rebar.config.
+package backend
 
 import (
 	"bufio"
-	"context"
 	"fmt"
 	"os"
 	"regexp"
 	"strings"
+	"time"
 
-	"github.com/google/go-github/v62/github"
-	"golang.org/x/mod/semver"
-	"golang.org/x/oauth2"
+	"Sbom/audit"
+	"Sbom/forge"
+	"Sbom/version"
 )
 
 // --- Data Structures ---
@@ -19,23 +20,23 @@ type DependencyInfo struct {
 	Name           string
 	CurrentVersion string
 	RepoURL        string
+	RepoPath       string // owner/repo on the hosting forge
+	RepoWebURL     string // Repository home page on the hosting forge
+	PinKind        string // "tag", "branch" or "ref" (commit SHA)
 	LatestVersion  string
 	UpdateNeeded   bool
+	Bump           version.Bump
+	ReleasesBehind int    // Tags between the current and the latest version
+	Archived       bool   // The upstream repository is archived
+	Compared       bool   // Measured against the latest tag, or the default branch for branch and ref pins
+	Error          string // Why the dependency could not be checked
 	Status         string
-}
-
-// --- GitHub Client Setup ---
 
-func createGitHubClient() *github.Client {
-	ctx := context.Background()
-	token := os.Getenv("GITHUB_TOKEN")
-	if token == "" {
-		fmt.Println("⚠️ Warning: GITHUB_TOKEN not set. Running unauthenticated (low rate limit).")
-		return github.NewClient(nil)
-	}
-	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
-	tc := oauth2.NewClient(ctx, ts)
-	return github.NewClient(tc)
+	// Branch and ref pins only
+	DefaultBranch string    // Upstream default branch the pin is measured against
+	CommitsBehind int       // Commits on the default branch the pinned commit lacks
+	PinnedDate    time.Time // Committer date of the pinned commit
+	IncludedIn    string    // Latest release tag that already contains the pinned commit
 }
 
 // --- File Reading and Parsing ---
@@ -75,20 +76,21 @@ func parseErlangDeps(configContent string) ([]DependencyInfo, error) {
 	cleanList = strings.ReplaceAll(cleanList, "{tag: ", "{tag, ")
 	cleanList = strings.ReplaceAll(cleanList, "}} % for R19 and below", "}}")
 
-	// Regex targets the common git/tag structure: {App, ".*", {git, "URL", {tag, "VERSION"}}}
-	reDep := regexp.MustCompile(`{([a-zA-Z0-9_@-]+),\s*".*?",\s*{git,\s*"(https://[^"]+)",\s*{tag,\s*"([^"]+)"}}}`)
+	// Regex targets the common git structure: {App, ".*", {git, "URL", {tag|branch|ref, "VALUE"}}}
+	reDep := regexp.MustCompile(`{([a-zA-Z0-9_@-]+),\s*".*?",\s*{git,\s*"([^"]+)",\s*{(tag|branch|ref),\s*"([^"]+)"}}}`)
 	matches := reDep.FindAllStringSubmatch(cleanList, -1)
 
 	if len(matches) == 0 {
-		return nil, fmt.Errorf("no standard git/tag dependencies found after cleanup")
+		return nil, fmt.Errorf("no standard git dependencies found after cleanup")
 	}
 
 	for _, match := range matches {
-		if len(match) == 4 {
+		if len(match) == 5 {
 			deps = append(deps, DependencyInfo{
 				Name:           match[1],
 				RepoURL:        match[2],
-				CurrentVersion: match[3],
+				PinKind:        match[3],
+				CurrentVersion: match[4],
 			})
 		}
 	}
@@ -96,148 +98,311 @@ func parseErlangDeps(configContent string) ([]DependencyInfo, error) {
 	return deps, nil
 }
 
-// parseGitHubURL: Extracts owner and repo from the Git URL
-func parseGitHubURL(url string) (owner, repo string) {
-	url = strings.TrimSuffix(url, ".git")
-	url = strings.TrimPrefix(url, "git://")
-	url = strings.TrimPrefix(url, "https://github.com/")
-
-	parts := strings.Split(url, "/")
-	if len(parts) >= 2 {
-		return parts[0], parts[1]
-	}
-	return "", ""
-}
-
-// findLatestVersion: Finds the latest version from GitHub tags/releases
-func findLatestVersion(client *github.Client, owner, repo string) (string, error) {
-	ctx := context.Background()
-	latestValidVersion := ""
-
+// findLatestVersion: Finds the latest version from the forge's tags/releases, under the dependency's version scheme.
+// When the forge API fails (rate limit, outage, missing token) the tags are read over git instead.
+func findLatestVersion(source forge.Forge, repository forge.Repository, repoURL string, scheme version.Scheme) (string, error) {
 	// 1. Try to get the latest Release first (most reliable)
-	release, _, relErr := client.Repositories.GetLatestRelease(ctx, owner, repo)
+	release, relErr := source.LatestRelease(repository)
 
-	if relErr == nil && release != nil {
-		return release.GetTagName(), nil
+	if relErr == nil && scheme.Valid(release.TagName) {
+		return release.TagName, nil
 	}
 
-	// 2. If release failed, list tags and find the latest semantically
-	tags, _, tagErr := client.Repositories.ListTags(ctx, owner, repo, &github.ListOptions{PerPage: 30})
+	// 2. If release failed, list tags and find the highest version
+	tags, tagErr := source.ListTags(repository)
 
+	if tagErr != nil && source.Name() != "git" {
+		fmt.Printf("   ⚠️ %s API failed (%v). Listing tags over git instead.\n", source.Name(), tagErr)
+		tags, tagErr = forge.ListRemoteTags(repoURL)
+	}
 	if tagErr != nil {
 		return "", fmt.Errorf("could not retrieve tags: %w", tagErr)
 	}
 
-	// Iterate through tags and find the highest semantic version
-	for _, tag := range tags {
-		tagName := tag.GetName()
-		verToCompare := tagName
-		if !strings.HasPrefix(verToCompare, "v") {
-			verToCompare = "v" + verToCompare
-		}
-
-		if semver.IsValid(verToCompare) {
-			if latestValidVersion == "" || semver.Compare(verToCompare, latestValidVersion) > 0 {
-				latestValidVersion = verToCompare
-			}
-		}
-	}
-
+	latestValidVersion := scheme.Latest(tags)
 	if latestValidVersion == "" {
-		return "", fmt.Errorf("no valid semantic version tags found")
+		return "", fmt.Errorf("no valid %s version tags found", scheme)
 	}
 
 	return latestValidVersion, nil
 }
 
 // checkUpdateAndCreateReport: Performs the update check
-func checkUpdateAndCreateReport(client *github.Client, deps []DependencyInfo) []DependencyInfo {
+func checkUpdateAndCreateReport(router *forge.Router, deps []DependencyInfo) []DependencyInfo {
 	var results []DependencyInfo
 
+	// Fetch archived state, releases and tags of all repositories in bulk where the forge allows it
+	var repositories []forge.Repository
+	for _, dep := range deps {
+		if repository, ok := forge.ParseRepoURL(dep.RepoURL); ok {
+			repositories = append(repositories, repository)
+		}
+	}
+	router.Prefetch(repositories)
+
 	for i := range deps {
 		dep := &deps[i]
-		owner, repo := parseGitHubURL(dep.RepoURL)
 
-		fmt.Printf("-> Checking %s (%s) from %s/%s\n", dep.Name, dep.CurrentVersion, owner, repo)
+		fmt.Printf("-> Checking %s (%s) from %s\n", dep.Name, dep.CurrentVersion, dep.RepoURL)
 
-		currentVer := dep.CurrentVersion
-		if !strings.HasPrefix(currentVer, "v") {
-			currentVer = "v" + currentVer
+		scheme := version.For("rebar", dep.Name)
+		if dep.PinKind == "tag" && !scheme.Valid(dep.CurrentVersion) {
+			dep.Error = fmt.Sprintf("%s is not a %s version", dep.CurrentVersion, scheme)
+			dep.Status = "❌ Invalid dependency details"
+			results = append(results, *dep)
+			continue
 		}
 
-		if owner == "" || repo == "" || !semver.IsValid(currentVer) {
+		source, repository, err := router.Resolve(dep.RepoURL)
+		if err != nil {
+			dep.Error = err.Error()
 			dep.Status = "❌ Invalid dependency details"
 			results = append(results, *dep)
 			continue
 		}
+		dep.RepoPath = repository.Path()
+		dep.RepoWebURL = source.WebURL(repository)
+
+		// Served from the prefetched metadata where the forge supports it
+		if archived, err := source.IsArchived(repository); err != nil {
+			fmt.Printf(" [ERROR] Could not fetch repo details for %s: %v\n", repository.Path(), err)
+		} else if archived {
+			dep.Archived = true
+			fmt.Printf(" [DEPRECATED] Repository %s is ARCHIVED.\n", repository.Path())
+		}
+
+		if dep.PinKind != "tag" {
+			checkCommitDistance(source, repository, dep, scheme)
+			results = append(results, *dep)
+			continue
+		}
 
-		latestVerWithV, err := findLatestVersion(client, owner, repo)
+		latestVer, err := findLatestVersion(source, repository, dep.RepoURL, scheme)
 		if err != nil {
-			dep.Status = fmt.Sprintf("❌ Error: %v", err)
+			dep.Error = err.Error()
+			dep.Status = "❌ Error: " + dep.Error
 			results = append(results, *dep)
 			continue
 		}
+		dep.Compared = true
 
-		if semver.Compare(latestVerWithV, currentVer) > 0 {
+		if scheme.Compare(latestVer, dep.CurrentVersion) > 0 {
 			dep.UpdateNeeded = true
-			dep.Status = "⬆️ Update Available"
+			dep.Bump = scheme.Classify(dep.CurrentVersion, latestVer)
+			if tags, err := source.ListTags(repository); err == nil {
+				dep.ReleasesBehind = scheme.Behind(dep.CurrentVersion, latestVer, tags)
+			}
+			dep.Status = audit.UpdateStatus(dep.Bump)
 		} else {
 			dep.UpdateNeeded = false
 			dep.Status = "✅ Up to Date"
 		}
+		if dep.Archived {
+			if dep.UpdateNeeded {
+				dep.Status = "⛔️ DEPRECATED (Update Needed)"
+			} else {
+				dep.Status = "⛔️ DEPRECATED (Up to date)"
+			}
+		}
 
-		dep.LatestVersion = strings.TrimPrefix(latestVerWithV, "v")
+		dep.LatestVersion = strings.TrimPrefix(latestVer, "v")
 		results = append(results, *dep)
 	}
 	return results
 }
 
-// printReport: Writes the results to the specified file in Markdown table format
-func printReport(results []DependencyInfo, filename string) error {
+// checkCommitDistance: Measures a branch- or commit-pinned dependency against the upstream default
+// branch: how many commits it is behind, how old the pinned commit is, and whether the latest
+// release already contains it (so the moving pin can become a tag)
+func checkCommitDistance(source forge.Forge, repository forge.Repository, dep *DependencyInfo, scheme version.Scheme) {
+	history, ok := source.(forge.CommitHistory)
+	if !ok {
+		dep.Status = fmt.Sprintf("⚠️ Pinned to %s (%s cannot compare commits)", dep.PinKind, source.Name())
+		return
+	}
+
+	defaultBranch, err := history.DefaultBranch(repository)
+	if err != nil {
+		dep.Error = fmt.Sprintf("could not get default branch: %v", err)
+		dep.Status = "❌ Error: " + dep.Error
+		return
+	}
+	dep.DefaultBranch = defaultBranch
+
+	pinned, err := history.Commit(repository, dep.CurrentVersion)
+	if err != nil {
+		dep.Error = fmt.Sprintf("could not resolve %s %s: %v", dep.PinKind, dep.CurrentVersion, err)
+		dep.Status = "❌ Error: " + dep.Error
+		return
+	}
+	dep.PinnedDate = pinned.Date
 
-	file, err := os.Create(filename)
+	comparison, err := history.Compare(repository, pinned.SHA, defaultBranch)
 	if err != nil {
-		// If file creation fails, report the error up the chain
-		return fmt.Errorf("could not create report file %s: %w", filename, err)
+		dep.Error = fmt.Sprintf("could not compare with %s: %v", defaultBranch, err)
+		dep.Status = "❌ Error: " + dep.Error
+		return
 	}
-	defer file.Close()
+	dep.CommitsBehind = comparison.AheadBy
 
-	writer := bufio.NewWriter(file)
-	defer writer.Flush()
+	// A release containing the pinned commit has nothing from it missing
+	if latestVer, err := findLatestVersion(source, repository, dep.RepoURL, scheme); err == nil {
+		dep.LatestVersion = strings.TrimPrefix(latestVer, "v")
+		if tagComparison, err := history.Compare(repository, pinned.SHA, latestVer); err == nil && tagComparison.BehindBy == 0 {
+			dep.IncludedIn = latestVer
+		}
+	}
 
-	// Markdown Content
-	writer.WriteString("## 📋 Erlang Dependency Update Audit\n\n")
-	writer.WriteString("This report compares the current tags in your `rebar.config` against the latest versions on GitHub.\n\n")
-	writer.WriteString("| # | Dependency | Status | Current Tag | Latest Tag | Repository |\n")
-	writer.WriteString("| :---: | :--- | :---: | :---: | :---: | :--- |\n")
+	age := int(time.Since(dep.PinnedDate).Hours() / 24)
+	switch {
+	case dep.PinKind == "branch" && dep.CurrentVersion == defaultBranch:
+		// Always at the head of the default branch, so there is nothing to be behind
+		dep.Status = fmt.Sprintf("🔀 Tracks `%s` (not reproducible), last commit %d days old", defaultBranch, age)
+	case dep.CommitsBehind == 0:
+		dep.Compared = true
+		dep.Status = fmt.Sprintf("✅ Up to date with `%s`, pinned commit %d days old", defaultBranch, age)
+	default:
+		dep.Compared = true
+		dep.UpdateNeeded = true
+		dep.Status = fmt.Sprintf("⏳ %d commits behind `%s`, pinned commit %d days old", dep.CommitsBehind, defaultBranch, age)
+	}
+	if dep.IncludedIn != "" {
+		dep.Status += fmt.Sprintf("; released in `%s`", dep.IncludedIn)
+	}
+	if dep.Archived {
+		dep.Status = "⛔️ DEPRECATED (Archived): " + dep.Status
+	}
+}
 
-	for i, dep := range results {
-		currentDisplay := strings.TrimPrefix(dep.CurrentVersion, "v")
-		latestDisplay := dep.LatestVersion
+// reportTemplate renders the rebar.config audit; users can replace it through REPORT_TEMPLATES
+var reportTemplate = audit.Template{
+	Name: "backend",
+	Text: `## 📋 Erlang Dependency Update Audit
 
-		statusDisplay := dep.Status
-		if dep.UpdateNeeded {
-			statusDisplay = "**" + statusDisplay + "**"
+This report compares the current tags in your {{code "rebar.config"}} against the latest versions on their forges (GitHub, GitLab, Gitea/Forgejo, Bitbucket). Branch- and commit-pinned dependencies are measured in commits behind the upstream default branch.
+
+{{summary .Report}}{{table .Results .Report.Findings}}`,
+	Funcs: map[string]interface{}{
+		"table": func(results []DependencyInfo, findings []audit.Finding) string {
+			return audit.Capture(func(writer *bufio.Writer) { writeTable(writer, results, findings) })
+		},
+	},
+}
+
+// printReport: Renders the results ([]DependencyInfo) to the specified file through reportTemplate
+func printReport(results []DependencyInfo, report *audit.Report, filename string) error {
+	return reportTemplate.Write(filename, audit.TemplateData{Report: report, Results: results})
+}
+
+// writeTable writes the dependency table (shared with combined scan reports) from the report's
+// findings; results, in the same order, add the pin kinds and repository links
+func writeTable(writer *bufio.Writer, results []DependencyInfo, findings []audit.Finding) {
+	writer.WriteString("| # | Dependency | Status | Change | Pinned To | Latest Tag | Repository |\n")
+	writer.WriteString("| :---: | :--- | :---: | :---: | :---: | :---: | :--- |\n")
+
+	for i, finding := range findings {
+		dep := results[i]
+		currentDisplay := fmt.Sprintf("`%s`", strings.TrimPrefix(finding.CurrentVersion, "v"))
+		switch dep.PinKind {
+		case "ref":
+			currentDisplay = fmt.Sprintf("`%.7s` (commit)", finding.CurrentVersion)
+		case "branch":
+			currentDisplay = fmt.Sprintf("`%s` (branch)", finding.CurrentVersion)
 		}
+		latestDisplay := finding.LatestVersion
 
-		owner, repo := parseGitHubURL(dep.RepoURL)
+		statusDisplay := finding.Status
+		if finding.UpdateNeeded {
+			statusDisplay = "**" + statusDisplay + "**"
+		}
+		statusDisplay = audit.AcceptedStatus(statusDisplay, finding)
 
 		// Link directly to the repository
-		repoLink := fmt.Sprintf("[%s/%s](https://github.com/%s/%s)", owner, repo, owner, repo)
-		if owner == "" {
+		repoLink := fmt.Sprintf("[%s](%s)", dep.RepoPath, dep.RepoWebURL)
+		if dep.RepoWebURL == "" {
 			repoLink = dep.RepoURL // Fallback if parsing failed
 		}
 
-		line := fmt.Sprintf("| %d | `%s` | %s | `%s` | `%s` | %s |\n",
-			i+1, dep.Name, statusDisplay, currentDisplay, latestDisplay, repoLink)
+		line := fmt.Sprintf("| %d | `%s` | %s | %s | %s | `%s` | %s |\n",
+			i+1, finding.Name, statusDisplay, audit.ChangeLabel(finding.Bump, finding.ReleasesBehind), currentDisplay, latestDisplay, repoLink)
 		writer.WriteString(line)
 	}
+}
+
+// auditConfig parses a rebar.config and checks every tag-, branch- and ref-pinned git dependency
+func auditConfig(router *forge.Router, configFileName string) ([]DependencyInfo, error) {
+	// 1. Read the file content
+	configContent, err := readConfigFile(configFileName)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Parse the dependencies
+	deps, err := parseErlangDeps(configContent)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing dependencies: %w", err)
+	}
+
+	// 3. Filter and prepare dependencies
+	var filteredDeps []DependencyInfo
+	for _, dep := range deps {
+		// Tags must be valid versions under the dependency's scheme; branches and refs are compared by commits
+		if dep.PinKind != "tag" || version.For("rebar", dep.Name).Valid(dep.CurrentVersion) {
+			filteredDeps = append(filteredDeps, dep)
+		}
+	}
+
+	if len(filteredDeps) == 0 {
+		fmt.Println("No valid Git dependencies found to audit.")
+		return nil, nil
+	}
+
+	fmt.Printf("Starting audit of %d Erlang dependencies...\n", len(filteredDeps))
+
+	// 4. Perform the checks
+	return checkUpdateAndCreateReport(router, filteredDeps), nil
+}
+
+// ScanManifest audits one rebar.config for combined scan reports; its table is written once the
+// findings are added to the report
+func ScanManifest(router *forge.Router, configFileName string) ([]audit.Finding, audit.TableWriter, error) {
+	results, err := auditConfig(router, configFileName)
+	if err != nil {
+		return nil, nil, err
+	}
+	table := func(writer *bufio.Writer, findings []audit.Finding) { writeTable(writer, results, findings) }
+	return toFindings(results), table, nil
+}
 
-	return nil
+// toFindings converts the audited dependencies into the ecosystem-neutral model
+func toFindings(results []DependencyInfo) []audit.Finding {
+	var findings []audit.Finding
+	for _, dep := range results {
+		findings = append(findings, audit.Finding{
+			Name:           dep.Name,
+			CurrentVersion: dep.CurrentVersion,
+			LatestVersion:  dep.LatestVersion,
+			Status:         dep.Status,
+			Compared:       dep.Compared,
+			Error:          dep.Error,
+			UpdateNeeded:   dep.UpdateNeeded,
+			Archived:       dep.Archived,
+			Bump:           dep.Bump,
+			ReleasesBehind: dep.ReleasesBehind,
+			CommitsBehind:  dep.CommitsBehind,
+			PinnedDate:     dep.PinnedDate,
+			IncludedIn:     dep.IncludedIn,
+		})
+	}
+	return findings
 }
 
-func main() {
-	const configFileName = "backend/rebar.config"
+// Run audits backend/rebar.config (or the rebar.config given as first argument)
+func Run(args []string) {
+	configFileName := "backend/rebar.config"
+	if len(args) > 0 {
+		configFileName = args[0]
+	}
 	const outputDir = "backend"
 	const outputFileName = "report.md"
 	outputFilePath := outputDir + "/" + outputFileName
@@ -250,51 +415,32 @@ func main() {
 		}
 	}
 
-	// 2. Read the file content
-	configContent, err := readConfigFile(configFileName)
-	if err != nil {
-		fmt.Printf("Fatal Error: %v\n", err)
-		os.Exit(1)
-	}
+	router := forge.NewRouter(forge.NewGitHubClient())
 
-	// 3. Parse the dependencies
-	deps, err := parseErlangDeps(configContent)
+	// 2. Parse and check the dependencies
+	report := audit.NewReport("backend", configFileName)
+	started := time.Now()
+	results, err := auditConfig(router, configFileName)
 	if err != nil {
-		fmt.Printf("Error parsing dependencies: %v\n", err)
+		fmt.Printf("Fatal Error: %v\n", err)
 		os.Exit(1)
 	}
-
-	// 4. Filter and prepare dependencies
-	var filteredDeps []DependencyInfo
-	for _, dep := range deps {
-		currentVer := dep.CurrentVersion
-		if !strings.HasPrefix(currentVer, "v") {
-			currentVer = "v" + currentVer
-		}
-		// Only proceed if the current version is valid SemVer (i.e., not a branch name like "main")
-		if semver.IsValid(currentVer) {
-			filteredDeps = append(filteredDeps, dep)
-		}
-	}
-
-	if len(filteredDeps) == 0 {
-		fmt.Println("No valid Git tag dependencies found to audit.")
+	if len(results) == 0 {
 		return
 	}
 
-	client := createGitHubClient()
-
-	fmt.Printf("Starting audit of %d Erlang dependencies...\n", len(filteredDeps))
-
-	// 5. Perform the checks
-	results := checkUpdateAndCreateReport(client, filteredDeps)
+	report.Add(audit.NewSource(configFileName, "rebar", started, toFindings(results), nil))
+	report.Finish()
 
-	// 6. Write the final Markdown report to the file
-	err = printReport(results, outputFilePath)
+	// 3. Write the final Markdown and JSON reports to the files
+	err = printReport(results, report, outputFilePath)
+	if err == nil {
+		err = report.WriteFiles(outputFilePath)
+	}
 	if err != nil {
 		fmt.Printf("Fatal Error writing report: %v\n", err)
 		os.Exit(1)
 	}
 
-	fmt.Printf("✅ Operation completed successfully. Results saved in **%s**.\n", outputFilePath)
+	fmt.Printf("✅ Operation completed successfully. Results saved in %s.\n", audit.OutputFiles(reportTemplate.Name, outputFilePath))
 }
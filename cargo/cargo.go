@@ -0,0 +1,721 @@
+// Package cargo audits Rust workspaces against the crates.io sparse index.
+package cargo
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"Sbom/audit"
+	"Sbom/internal/toml"
+	"Sbom/version"
+
+	"golang.org/x/mod/semver"
+)
+
+// --- Data Structures ---
+
+type CrateDependency struct {
+	Name           string
+	Member         string // Workspace member (crate) declaring the dependency
+	Manifest       string // Member manifest declaring the dependency ("" for the root manifest)
+	Kind           string // dependencies, dev-dependencies or build-dependencies
+	Requirement    string // Version requirement as written in Cargo.toml
+	CurrentVersion string // Locked version from Cargo.lock, or the requirement's base version
+	Source         string // registry, git or path
+	GitURL         string
+	GitRef         string
+	Path           string
+	LatestVersion  string
+	UpdateNeeded   bool
+	Yanked         bool
+	Bump           version.Bump
+	ReleasesBehind int    // Non-yanked versions published between the current and the latest version
+	Error          string // Why the crate could not be checked
+	Status         string
+}
+
+type LockedPackage struct {
+	Name    string
+	Version string
+	Source  string
+}
+
+// IndexEntry is one line of a crate file in the crates.io sparse index
+type IndexEntry struct {
+	Name   string `json:"name"`
+	Vers   string `json:"vers"`
+	Yanked bool   `json:"yanked"`
+}
+
+const sparseIndexURL = "https://index.crates.io"
+
+// --- Cargo.toml and Cargo.lock Parsing ---
+
+type cargoManifest struct {
+	PackageName   string
+	Members       []string
+	Exclude       []string
+	Dependencies  []CrateDependency
+	WorkspaceDeps map[string]map[string]string // [workspace.dependencies], keyed by crate name
+}
+
+func readManifest(filename string) (cargoManifest, error) {
+	var manifest cargoManifest
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return manifest, fmt.Errorf("error reading %s: %w", filename, err)
+	}
+	manifest.WorkspaceDeps = make(map[string]map[string]string)
+
+	// Table-form dependencies ([dependencies.uuid]) are collected per section first
+	for _, section := range toml.Parse(string(data)) {
+		switch {
+		case section.Name == "package":
+			for _, entry := range section.Entries {
+				if entry.Key == "name" {
+					manifest.PackageName = toml.String(entry.Value)
+				}
+			}
+
+		case section.Name == "workspace":
+			for _, entry := range section.Entries {
+				switch entry.Key {
+				case "members":
+					manifest.Members = toml.Array(entry.Value)
+				case "exclude":
+					manifest.Exclude = toml.Array(entry.Value)
+				}
+			}
+
+		case section.Name == "workspace.dependencies":
+			for name, spec := range dependencySpecs(section.Entries) {
+				manifest.WorkspaceDeps[name] = spec
+			}
+
+		default:
+			kind, tableName := dependencySectionKind(section.Name)
+			if kind == "" {
+				continue
+			}
+			specs := dependencySpecs(section.Entries)
+			if tableName != "" {
+				// [dependencies.uuid]: every entry of the section belongs to one crate
+				spec := make(map[string]string)
+				for _, entry := range section.Entries {
+					spec[entry.Key] = entry.Value
+				}
+				specs = map[string]map[string]string{tableName: spec}
+			}
+			for name, spec := range specs {
+				manifest.Dependencies = append(manifest.Dependencies, newCrateDependency(name, kind, spec))
+			}
+		}
+	}
+
+	sort.Slice(manifest.Dependencies, func(i, j int) bool {
+		if manifest.Dependencies[i].Kind != manifest.Dependencies[j].Kind {
+			return manifest.Dependencies[i].Kind < manifest.Dependencies[j].Kind
+		}
+		return manifest.Dependencies[i].Name < manifest.Dependencies[j].Name
+	})
+	return manifest, nil
+}
+
+// dependencySectionKind: Maps a section name such as "dev-dependencies",
+// "target.'cfg(unix)'.dependencies" or "dependencies.uuid" to its dependency kind
+// and, for the table form, the crate name.
+func dependencySectionKind(name string) (kind, tableName string) {
+	if strings.HasPrefix(name, "target.") {
+		// Strip the target cfg, which may itself contain dots
+		if idx := strings.LastIndex(name, "dependencies"); idx != -1 {
+			start := strings.LastIndex(name[:idx], ".")
+			name = name[start+1:]
+		}
+	}
+	for _, k := range []string{"dependencies", "dev-dependencies", "build-dependencies"} {
+		if name == k {
+			return k, ""
+		}
+		if strings.HasPrefix(name, k+".") {
+			return k, strings.TrimPrefix(name, k+".")
+		}
+	}
+	return "", ""
+}
+
+// dependencySpecs: Turns the entries of a dependency section into per-crate specs.
+// Handles plain versions, inline tables and dotted keys (tracing.workspace = true).
+func dependencySpecs(entries []toml.Entry) map[string]map[string]string {
+	specs := make(map[string]map[string]string)
+	for _, entry := range entries {
+		name, attr, dotted := strings.Cut(entry.Key, ".")
+		spec, ok := specs[name]
+		if !ok {
+			spec = make(map[string]string)
+			specs[name] = spec
+		}
+
+		switch {
+		case dotted:
+			spec[attr] = entry.Value
+		case strings.HasPrefix(entry.Value, "{"):
+			for k, v := range toml.InlineTable(entry.Value) {
+				spec[k] = v
+			}
+		default:
+			spec["version"] = entry.Value
+		}
+	}
+	return specs
+}
+
+func newCrateDependency(name, kind string, spec map[string]string) CrateDependency {
+	dep := CrateDependency{
+		Name:        name,
+		Kind:        kind,
+		Requirement: toml.String(spec["version"]),
+		Source:      "registry",
+	}
+	// Renamed dependencies (foo = { package = "bar" }) are published under the package name
+	if pkg, ok := spec["package"]; ok {
+		dep.Name = toml.String(pkg)
+	}
+	if toml.String(spec["workspace"]) == "true" {
+		dep.Source = "workspace"
+	}
+	if git, ok := spec["git"]; ok {
+		dep.Source = "git"
+		dep.GitURL = toml.String(git)
+		for _, ref := range []string{"tag", "branch", "rev"} {
+			if val, ok := spec[ref]; ok {
+				dep.GitRef = ref + " " + toml.String(val)
+			}
+		}
+	}
+	if path, ok := spec["path"]; ok && dep.Source != "git" {
+		dep.Source = "path"
+		dep.Path = toml.String(path)
+	}
+	return dep
+}
+
+// resolveWorkspaceDependency: Fills in a `workspace = true` dependency from [workspace.dependencies]
+func resolveWorkspaceDependency(dep CrateDependency, workspaceDeps map[string]map[string]string) CrateDependency {
+	spec, ok := workspaceDeps[dep.Name]
+	if !ok {
+		dep.Source = "registry"
+		return dep
+	}
+	resolved := newCrateDependency(dep.Name, dep.Kind, spec)
+	resolved.Member = dep.Member
+	resolved.Manifest = dep.Manifest
+	return resolved
+}
+
+// findWorkspaceMembers: Expands the member globs of a workspace root into manifest paths
+func findWorkspaceMembers(rootDir string, manifest cargoManifest) []string {
+	excluded := make(map[string]bool)
+	for _, pattern := range manifest.Exclude {
+		matches, _ := filepath.Glob(filepath.Join(rootDir, pattern))
+		for _, match := range matches {
+			excluded[filepath.Clean(match)] = true
+		}
+	}
+
+	var members []string
+	for _, pattern := range manifest.Members {
+		matches, err := filepath.Glob(filepath.Join(rootDir, pattern))
+		if err != nil {
+			fmt.Printf("⚠️ Warning: Invalid workspace member pattern '%s': %v\n", pattern, err)
+			continue
+		}
+		for _, match := range matches {
+			manifestPath := filepath.Join(match, "Cargo.toml")
+			if excluded[filepath.Clean(match)] {
+				continue
+			}
+			if _, err := os.Stat(manifestPath); err == nil {
+				members = append(members, manifestPath)
+			}
+		}
+	}
+	sort.Strings(members)
+	return members
+}
+
+// readLockfile: Reads [[package]] entries from Cargo.lock, keyed by crate name
+func readLockfile(filename string) (map[string][]LockedPackage, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", filename, err)
+	}
+
+	locked := make(map[string][]LockedPackage)
+	for _, section := range toml.Parse(string(data)) {
+		if !section.Array || section.Name != "package" {
+			continue
+		}
+		var pkg LockedPackage
+		for _, entry := range section.Entries {
+			switch entry.Key {
+			case "name":
+				pkg.Name = toml.String(entry.Value)
+			case "version":
+				pkg.Version = toml.String(entry.Value)
+			case "source":
+				pkg.Source = toml.String(entry.Value)
+			}
+		}
+		if pkg.Name != "" {
+			locked[pkg.Name] = append(locked[pkg.Name], pkg)
+		}
+	}
+	return locked, nil
+}
+
+// lockedVersionFor: Picks the highest locked version the requirement accepts under Cargo's caret
+// rules (Cargo.lock may contain several versions of the same crate).
+func lockedVersionFor(dep CrateDependency, locked map[string][]LockedPackage) (LockedPackage, bool) {
+	candidates := locked[dep.Name]
+	if len(candidates) == 0 {
+		return LockedPackage{}, false
+	}
+	base := requirementBase(dep.Requirement)
+
+	var best LockedPackage
+	for _, pkg := range candidates {
+		if dep.Source == "git" && !strings.HasPrefix(pkg.Source, "git+") {
+			continue
+		}
+		if base != "" && !caretCompatible(base, pkg.Version) {
+			continue
+		}
+		if best.Version == "" || semver.Compare(toSemver(pkg.Version), toSemver(best.Version)) > 0 {
+			best = pkg
+		}
+	}
+	return best, best.Version != ""
+}
+
+// caretCompatible: Reports whether version is compatible with the (possibly partial) requirement
+// version under Cargo's caret rules: the leftmost non-zero component and everything before it must
+// match, so ^1.2 accepts 1.x, ^0.2 only 0.2.x and ^0.0.3 only 0.0.3. When every given component
+// is zero they all must match: ^0 accepts 0.x, ^0.0 accepts 0.0.x.
+func caretCompatible(requirement, version string) bool {
+	wanted := versionNumbers(requirement)
+	have := versionNumbers(version)
+	if len(wanted) == 0 || len(have) < len(wanted) {
+		return false
+	}
+	for i, number := range wanted {
+		if have[i] != number {
+			return false
+		}
+		if number != 0 {
+			return true
+		}
+	}
+	return true
+}
+
+// versionNumbers: The numeric components of a version, without pre-release or build suffixes
+func versionNumbers(ver string) []int {
+	ver = strings.TrimPrefix(strings.TrimSpace(ver), "v")
+	if index := strings.IndexAny(ver, "-+"); index >= 0 {
+		ver = ver[:index]
+	}
+	var numbers []int
+	for _, part := range strings.Split(ver, ".") {
+		number, err := strconv.Atoi(part)
+		if err != nil {
+			return nil
+		}
+		numbers = append(numbers, number)
+	}
+	return numbers
+}
+
+// requirementBase: Strips Cargo requirement operators ("^1.2", "~0.10.5", ">=1, <2") down to a version
+func requirementBase(req string) string {
+	req = strings.Split(req, ",")[0]
+	req = strings.TrimFunc(req, func(r rune) bool {
+		return strings.ContainsRune("^~=<> *", r)
+	})
+	return req
+}
+
+// toSemver: Pads partial versions ("1.0" -> "v1.0.0") for golang.org/x/mod/semver
+func toSemver(ver string) string {
+	if ver == "" {
+		return ""
+	}
+	if !strings.HasPrefix(ver, "v") {
+		ver = "v" + ver
+	}
+	if semver.IsValid(ver) {
+		return semver.Canonical(ver)
+	}
+	return ver
+}
+
+// --- crates.io Sparse Index Client ---
+
+// sparseIndexPath: Returns the index file path for a crate, following the registry layout rules
+func sparseIndexPath(name string) string {
+	name = strings.ToLower(name)
+	switch len(name) {
+	case 1:
+		return "1/" + name
+	case 2:
+		return "2/" + name
+	case 3:
+		return "3/" + name[:1] + "/" + name
+	default:
+		return name[:2] + "/" + name[2:4] + "/" + name
+	}
+}
+
+func fetchIndexEntries(name string) ([]IndexEntry, error) {
+	url := fmt.Sprintf("%s/%s", sparseIndexURL, sparseIndexPath(name))
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("crates.io index returned status %d for crate %s", resp.StatusCode, name)
+	}
+
+	// One JSON document per published version, oldest first
+	var entries []IndexEntry
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var entry IndexEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// latestIndexVersion: Finds the highest non-yanked version, ignoring pre-releases
+// unless the current version is itself a pre-release.
+func latestIndexVersion(entries []IndexEntry, currentVer string) string {
+	allowPrerelease := semver.Prerelease(currentVer) != ""
+	latest := ""
+	for _, entry := range entries {
+		ver := toSemver(entry.Vers)
+		if entry.Yanked || !semver.IsValid(ver) {
+			continue
+		}
+		if semver.Prerelease(ver) != "" && !allowPrerelease {
+			continue
+		}
+		if latest == "" || semver.Compare(ver, latest) > 0 {
+			latest = ver
+		}
+	}
+	return latest
+}
+
+// --- Core Check Logic ---
+
+func checkCrateUpdate(dep CrateDependency, locked map[string][]LockedPackage, cache map[string][]IndexEntry) CrateDependency {
+	lockedPkg, isLocked := lockedVersionFor(dep, locked)
+
+	switch dep.Source {
+	case "path":
+		dep.CurrentVersion = lockedPkg.Version
+		dep.Status = "📁 Path dependency (not published)"
+		return dep
+	case "git":
+		dep.CurrentVersion = lockedPkg.Version
+		dep.Status = "📌 Git source"
+		if dep.GitRef != "" {
+			dep.Status += " (" + dep.GitRef + ")"
+		}
+		// Locked git sources carry the resolved commit after '#'
+		if _, commit, found := strings.Cut(lockedPkg.Source, "#"); found && len(commit) >= 7 {
+			dep.Status += fmt.Sprintf(" @ `%s`", commit[:7])
+		}
+		return dep
+	}
+
+	if isLocked {
+		dep.CurrentVersion = lockedPkg.Version
+	} else {
+		dep.CurrentVersion = requirementBase(dep.Requirement)
+	}
+	currentVer := toSemver(dep.CurrentVersion)
+	if !semver.IsValid(currentVer) {
+		dep.Error = "invalid version requirement"
+		dep.Status = "❌ Invalid version requirement"
+		return dep
+	}
+
+	entries, ok := cache[dep.Name]
+	if !ok {
+		var err error
+		entries, err = fetchIndexEntries(dep.Name)
+		if err != nil {
+			dep.Error = err.Error()
+			dep.Status = "❌ Error: " + dep.Error
+			return dep
+		}
+		cache[dep.Name] = entries
+	}
+
+	for _, entry := range entries {
+		if toSemver(entry.Vers) == currentVer && entry.Yanked {
+			dep.Yanked = true
+		}
+	}
+
+	latest := latestIndexVersion(entries, currentVer)
+	if latest == "" {
+		dep.Error = "no published versions found"
+		dep.Status = "❌ Error: " + dep.Error
+		return dep
+	}
+	dep.LatestVersion = strings.TrimPrefix(latest, "v")
+	dep.UpdateNeeded = semver.Compare(latest, currentVer) > 0
+
+	if dep.UpdateNeeded {
+		scheme := version.For("cargo", dep.Name)
+		var published []string
+		for _, entry := range entries {
+			if !entry.Yanked {
+				published = append(published, entry.Vers)
+			}
+		}
+		dep.Bump = scheme.Classify(currentVer, latest)
+		dep.ReleasesBehind = scheme.Behind(currentVer, latest, published)
+	}
+
+	switch {
+	case dep.Yanked:
+		dep.Status = "🚫 YANKED (Update Required)"
+	case dep.UpdateNeeded:
+		dep.Status = audit.UpdateStatus(dep.Bump)
+	default:
+		dep.Status = "✅ Up to Date"
+	}
+	return dep
+}
+
+// collectDependencies: Reads the root manifest and every workspace member,
+// resolving workspace-inherited dependencies against the root.
+func collectDependencies(rootManifestPath string) ([]CrateDependency, error) {
+	root, err := readManifest(rootManifestPath)
+	if err != nil {
+		return nil, err
+	}
+	rootDir := filepath.Dir(rootManifestPath)
+
+	var deps []CrateDependency
+	addMember := func(manifest cargoManifest, member, path string) {
+		for _, dep := range manifest.Dependencies {
+			dep.Member = member
+			dep.Manifest = path
+			if dep.Source == "workspace" {
+				dep = resolveWorkspaceDependency(dep, root.WorkspaceDeps)
+			}
+			deps = append(deps, dep)
+		}
+	}
+
+	if root.PackageName != "" {
+		addMember(root, root.PackageName, "")
+	}
+	for _, memberPath := range findWorkspaceMembers(rootDir, root) {
+		member, err := readManifest(memberPath)
+		if err != nil {
+			fmt.Printf("⚠️ Warning: Skipping workspace member: %v\n", err)
+			continue
+		}
+		name := member.PackageName
+		if name == "" {
+			name = filepath.Base(filepath.Dir(memberPath))
+		}
+		addMember(member, name, memberPath)
+	}
+	return deps, nil
+}
+
+// --- Output Function (Markdown Table) ---
+
+// reportTemplate is the default crate report, the table shared with scan reports under a heading
+var reportTemplate = audit.Template{
+	Name: "cargo",
+	Text: `## 🦀 Rust Crate Update Audit
+
+This report compares the crates locked in your {{code "Cargo.lock"}} (or required in {{code "Cargo.toml"}}) against the crates.io index.
+
+{{summary .Report}}{{table .Results .Report.Findings}}`,
+	Funcs: map[string]interface{}{
+		"table": func(results []CrateDependency, findings []audit.Finding) string {
+			return audit.Capture(func(writer *bufio.Writer) { writeTable(writer, results, findings) })
+		},
+	},
+}
+
+// printReport: Renders the results ([]CrateDependency) through reportTemplate
+func printReport(results []CrateDependency, report *audit.Report, filename string) error {
+	return reportTemplate.Write(filename, audit.TemplateData{Report: report, Results: results})
+}
+
+// writeTable writes the crate table (shared with combined scan reports) from the report's findings;
+// results, in the same order, add the workspace members, kinds and sources
+func writeTable(writer *bufio.Writer, results []CrateDependency, findings []audit.Finding) {
+	writer.WriteString("| # | Crate | Workspace Member | Kind | Status | Change | Current Version | Latest Version | Source |\n")
+	writer.WriteString("| :---: | :--- | :--- | :---: | :---: | :---: | :---: | :---: | :--- |\n")
+
+	for i, finding := range findings {
+		dep := results[i]
+		statusDisplay := finding.Status
+		if finding.UpdateNeeded || finding.Vulnerable {
+			statusDisplay = "**" + statusDisplay + "**"
+		}
+		statusDisplay = audit.AcceptedStatus(statusDisplay, finding)
+
+		sourceDisplay := fmt.Sprintf("[crates.io](https://crates.io/crates/%s)", finding.Name)
+		switch dep.Source {
+		case "git":
+			sourceDisplay = dep.GitURL
+		case "path":
+			sourceDisplay = "`" + dep.Path + "`"
+		}
+
+		line := fmt.Sprintf("| %d | `%s` | %s | %s | %s | %s | `%s` | `%s` | %s |\n",
+			i+1, finding.Name, dep.Member, dep.Kind, statusDisplay, audit.ChangeLabel(finding.Bump, finding.ReleasesBehind), finding.CurrentVersion, finding.LatestVersion, sourceDisplay)
+		writer.WriteString(line)
+	}
+}
+
+// auditWorkspace checks every dependency of a Cargo.toml and its workspace members,
+// using the Cargo.lock next to it when present.
+func auditWorkspace(manifestFileName string) ([]CrateDependency, error) {
+	lockFileName := filepath.Join(filepath.Dir(manifestFileName), "Cargo.lock")
+
+	// 1. Read the workspace root and all of its members
+	deps, err := collectDependencies(manifestFileName)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(deps) == 0 {
+		fmt.Println("No crate dependencies found to audit.")
+		return nil, nil
+	}
+
+	// 2. The lockfile is optional; without it the requirement's base version is audited
+	locked, err := readLockfile(lockFileName)
+	if err != nil {
+		fmt.Printf("⚠️ Warning: %v. Falling back to Cargo.toml requirements.\n", err)
+		locked = map[string][]LockedPackage{}
+	}
+
+	fmt.Printf("Starting audit of %d crate dependencies...\n", len(deps))
+
+	// 3. Perform the checks (each crate's index file is fetched only once)
+	cache := make(map[string][]IndexEntry)
+	var results []CrateDependency
+	for _, dep := range deps {
+		fmt.Printf("-> Checking crate %s %s (%s, %s)\n", dep.Name, dep.Requirement, dep.Member, dep.Source)
+		results = append(results, checkCrateUpdate(dep, locked, cache))
+	}
+	return results, nil
+}
+
+// WorkspaceMembers lists the member manifests audited together with a workspace root,
+// so scan mode does not report them twice
+func WorkspaceMembers(manifestFileName string) []string {
+	manifest, err := readManifest(manifestFileName)
+	if err != nil {
+		return nil
+	}
+	return findWorkspaceMembers(filepath.Dir(manifestFileName), manifest)
+}
+
+// ScanManifest audits one Cargo.toml (with its workspace members) for combined scan reports; its
+// table is written once the findings are added to the report
+func ScanManifest(manifestFileName string) ([]audit.Finding, audit.TableWriter, error) {
+	results, err := auditWorkspace(manifestFileName)
+	if err != nil {
+		return nil, nil, err
+	}
+	table := func(writer *bufio.Writer, findings []audit.Finding) { writeTable(writer, results, findings) }
+	return toFindings(results), table, nil
+}
+
+// toFindings converts the audited crates into the ecosystem-neutral model
+func toFindings(results []CrateDependency) []audit.Finding {
+	var findings []audit.Finding
+	for _, dep := range results {
+		var advisories []string
+		if dep.Yanked {
+			advisories = append(advisories, fmt.Sprintf("%s %s is yanked from crates.io", dep.Name, dep.CurrentVersion))
+		}
+		findings = append(findings, audit.Finding{
+			Name:           dep.Name,
+			CurrentVersion: dep.CurrentVersion,
+			LatestVersion:  dep.LatestVersion,
+			Status:         dep.Status,
+			Compared:       dep.LatestVersion != "",
+			Error:          dep.Error,
+			UpdateNeeded:   dep.UpdateNeeded,
+			Vulnerable:     dep.Yanked,
+			Bump:           dep.Bump,
+			ReleasesBehind: dep.ReleasesBehind,
+			Advisories:     advisories,
+			Manifest:       dep.Manifest,
+		})
+	}
+	return findings
+}
+
+// Run audits cargo/Cargo.toml (or the Cargo.toml given as first argument)
+func Run(args []string) {
+	manifestFileName := "cargo/Cargo.toml"
+	if len(args) > 0 {
+		manifestFileName = args[0]
+	}
+	const outputFilePath = "cargo/report.md"
+
+	report := audit.NewReport("cargo", manifestFileName)
+	started := time.Now()
+	results, err := auditWorkspace(manifestFileName)
+	if err != nil {
+		fmt.Printf("Fatal Error: %v\n", err)
+		os.Exit(1)
+	}
+	if len(results) == 0 {
+		return
+	}
+
+	report.Add(audit.NewSource(manifestFileName, "cargo", started, toFindings(results), nil))
+	report.Finish()
+
+	// 4. Write the final Markdown and JSON reports
+	err = printReport(results, report, outputFilePath)
+	if err == nil {
+		err = report.WriteFiles(outputFilePath)
+	}
+	if err != nil {
+		fmt.Printf("Fatal Error writing report: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("✅ Operation completed successfully. Results saved in %s.\n", audit.OutputFiles(reportTemplate.Name, outputFilePath))
+}
@@ -0,0 +1,79 @@
+package cargo
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"Sbom/audit"
+)
+
+func TestLockedVersionFor(t *testing.T) {
+	locked := map[string][]LockedPackage{
+		"rand":  {{Name: "rand", Version: "0.7.3"}, {Name: "rand", Version: "0.8.5"}},
+		"serde": {{Name: "serde", Version: "0.9.15"}, {Name: "serde", Version: "1.0.197"}},
+		"tiny":  {{Name: "tiny", Version: "0.0.3"}, {Name: "tiny", Version: "0.0.4"}},
+	}
+	tests := []struct {
+		name        string
+		requirement string
+		want        string
+		found       bool
+	}{
+		{"rand", "0.7", "0.7.3", true},
+		{"rand", "^0.8.1", "0.8.5", true},
+		{"rand", "0", "0.8.5", true},
+		{"rand", "0.6", "", false},
+		{"serde", "1.0.100", "1.0.197", true},
+		{"serde", "0.9", "0.9.15", true},
+		{"tiny", "0.0.3", "0.0.3", true},
+		{"tiny", "0.0", "0.0.4", true},
+		{"tiny", "0.0.5", "", false},
+	}
+	for _, test := range tests {
+		t.Run(test.name+" "+test.requirement, func(t *testing.T) {
+			pkg, found := lockedVersionFor(CrateDependency{Name: test.name, Requirement: test.requirement, Source: "registry"}, locked)
+			if found != test.found || pkg.Version != test.want {
+				t.Errorf("got %q (%v), want %q (%v)", pkg.Version, found, test.want, test.found)
+			}
+		})
+	}
+}
+
+func TestWriteTableRendersReportFindings(t *testing.T) {
+	results := []CrateDependency{
+		{Name: "serde", Member: "app", Kind: "dependencies", Source: "registry", CurrentVersion: "1.0.0", LatestVersion: "2.0.0", UpdateNeeded: true, Status: "⚠️ Major Update"},
+		{Name: "rand", Member: "app", Kind: "dependencies", Source: "path", Path: "../rand", CurrentVersion: "0.8.5", Status: "✅ Up to date"},
+	}
+	accepted := audit.BaselineEntry{Ecosystem: "cargo", Name: "serde", Version: "1.0.0", Issue: audit.Outdated, Expires: "2099-01-01"}
+	tests := []struct {
+		name     string
+		findings []audit.Finding
+		want     []string
+	}{
+		{
+			name:     "as audited",
+			findings: toFindings(results),
+			want:     []string{"| 1 | `serde` | app | dependencies | **⚠️ Major Update** |", "| 2 | `rand` | app | dependencies | ✅ Up to date |", "`../rand`"},
+		},
+		{
+			name: "accepted by the baseline",
+			findings: func() []audit.Finding {
+				findings := toFindings(results)
+				findings[0].Accepted = []audit.BaselineEntry{accepted}
+				return findings
+			}(),
+			want: []string{"**⚠️ Major Update** 🔕 outdated accepted until 2099-01-01"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			table := audit.Capture(func(writer *bufio.Writer) { writeTable(writer, results, test.findings) })
+			for _, want := range test.want {
+				if !strings.Contains(table, want) {
+					t.Errorf("table does not contain %q:\n%s", want, table)
+				}
+			}
+		})
+	}
+}
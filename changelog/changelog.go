@@ -0,0 +1,333 @@
+// Package changelog gathers the release notes of every release between the version in use and
+// the latest one, from the forge's releases or, when a project publishes none, its CHANGELOG.md.
+package changelog
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"Sbom/forge"
+	"Sbom/version"
+)
+
+// changelogFiles are tried in order when a repository has no releases
+var changelogFiles = []string{"CHANGELOG.md", "CHANGES.md", "HISTORY.md"}
+
+// ReleaseNote is one release an upgrade skips over, as shown in reports
+type ReleaseNote struct {
+	Tag       string    `json:"tag"`
+	Name      string    `json:"name,omitempty"`
+	URL       string    `json:"url,omitempty"`      // Release page, or the repository page for entries of a changelog file
+	Published time.Time `json:"published,omitzero"` // Zero when unknown
+	Body      string    `json:"body,omitempty"`
+	Source    string    `json:"source"` // Where the note came from: "GitHub releases", "CHANGELOG.md", ...
+}
+
+// Title is the heading of the note: its name, with the tag when the name does not mention it
+func (n ReleaseNote) Title() string {
+	switch {
+	case n.Name == "":
+		return n.Tag
+	case !strings.Contains(n.Name, n.Tag):
+		return fmt.Sprintf("%s (%s)", n.Name, n.Tag)
+	}
+	return n.Name
+}
+
+// Notes are the releases an upgrade skips over, newest first
+type Notes struct {
+	Releases []ReleaseNote
+	File     string // The changelog file the notes were parsed from; empty for forge releases
+}
+
+// newNotes: Converts forge releases into release notes from source, linked by link
+func newNotes(releases []forge.Release, source string, link func(forge.Release) string) []ReleaseNote {
+	var notes []ReleaseNote
+	for _, release := range releases {
+		notes = append(notes, ReleaseNote{
+			Tag:       release.TagName,
+			Name:      release.Name,
+			URL:       link(release),
+			Published: release.PublishedAt,
+			Body:      release.Body,
+			Source:    source,
+		})
+	}
+	return notes
+}
+
+// TagVersion: Normalises a release tag to a version: monorepo prefixes ("pkg@1.2.3",
+// "release/1.2.3") are dropped and a "v" is prepended
+func TagVersion(tag string) string {
+	if index := strings.LastIndexAny(tag, "@/"); index >= 0 {
+		tag = tag[index+1:]
+	}
+	if !strings.HasPrefix(tag, "v") {
+		tag = "v" + tag
+	}
+	return tag
+}
+
+// Collect: Pages through the releases until they are at or below current by version, and keeps
+// those newer than current up to and including latest. Tags that are no version under the scheme
+// (nightlies, ...) do not hold the paging up. Falls back to the repository's changelog file when
+// the forge lists no release in that range.
+func Collect(source forge.Forge, repo forge.Repository, scheme version.Scheme, current, latest string) (Notes, error) {
+	releases, err := source.ReleasesUntil(repo, func(release forge.Release) bool {
+		tag := TagVersion(release.TagName)
+		return !scheme.Valid(tag) || scheme.Compare(tag, current) <= 0
+	})
+	if err != nil {
+		return Notes{}, err
+	}
+	if between := Between(releases, scheme, current, latest); len(between) > 0 {
+		releaseURL := func(release forge.Release) string { return source.ReleaseURL(repo, release.TagName) }
+		return Notes{Releases: newNotes(between, source.Name()+" releases", releaseURL)}, nil
+	}
+
+	for _, file := range changelogFiles {
+		content, err := source.ReadFile(repo, file)
+		if err != nil {
+			continue
+		}
+		if between := Between(Parse(string(content)), scheme, current, latest); len(between) > 0 {
+			repoURL := func(forge.Release) string { return source.WebURL(repo) }
+			return Notes{Releases: newNotes(between, file, repoURL), File: file}, nil
+		}
+	}
+	return Notes{}, nil
+}
+
+// Between: Keeps the releases newer than current and not newer than latest, newest first.
+// An invalid latest puts no upper bound on the range.
+func Between(releases []forge.Release, scheme version.Scheme, current, latest string) []forge.Release {
+	seen := make(map[string]bool)
+	var result []forge.Release
+	for _, release := range releases {
+		tag := TagVersion(release.TagName)
+		if !scheme.Valid(tag) || seen[tag] || scheme.Compare(tag, current) <= 0 {
+			continue
+		}
+		if scheme.Valid(latest) && scheme.Compare(tag, latest) > 0 {
+			continue
+		}
+		seen[tag] = true
+		result = append(result, release)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return scheme.Compare(TagVersion(result[i].TagName), TagVersion(result[j].TagName)) > 0
+	})
+	return result
+}
+
+// --- CHANGELOG.md Parsing ---
+
+// versionHeading matches the section headings of common changelog styles:
+// "## [1.2.3] - 2024-01-01" (Keep a Changelog), "## v1.2.3", "### 1.2.3 (2024-01-01)", "# Version 1.2"
+var (
+	versionHeading = regexp.MustCompile(`^#{1,4}\s+(?:\[\s*)?(?:(?i:version|release)\s+)?(v?\d+(?:\.\d+)+(?:-[0-9A-Za-z.-]+)?)`)
+	headingDate    = regexp.MustCompile(`\d{4}-\d{2}-\d{2}`)
+)
+
+// Parse: Splits a Markdown changelog into one release per version heading; the text up to
+// the next version heading is the release body
+func Parse(markdown string) []forge.Release {
+	var releases []forge.Release
+	var current *forge.Release
+	var body []string
+
+	flush := func() {
+		if current != nil {
+			current.Body = strings.TrimSpace(strings.Join(body, "\n"))
+			releases = append(releases, *current)
+		}
+		body = nil
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(markdown, "\r\n", "\n"), "\n") {
+		if match := versionHeading.FindStringSubmatch(line); match != nil {
+			flush()
+			current = &forge.Release{TagName: match[1], Name: strings.TrimSpace(strings.TrimLeft(line, "#"))}
+			if date := headingDate.FindString(line); date != "" {
+				current.PublishedAt, _ = time.Parse("2006-01-02", date)
+			}
+			continue
+		}
+		if current != nil {
+			body = append(body, line)
+		}
+	}
+	flush()
+	return releases
+}
+
+// --- Consolidation ---
+
+// Consolidate: Joins the notes into one Markdown upgrade changelog, newest release first
+func Consolidate(notes Notes) string {
+	var builder strings.Builder
+	if notes.File != "" {
+		builder.WriteString(fmt.Sprintf("> Parsed from `%s` (no releases published).\n\n", notes.File))
+	}
+	for _, release := range notes.Releases {
+		title := release.Title()
+		if release.URL != "" && notes.File == "" {
+			title = fmt.Sprintf("[%s](%s)", title, release.URL)
+		}
+		if !release.Published.IsZero() {
+			title += " — " + release.Published.Format("2006-01-02")
+		}
+		builder.WriteString(fmt.Sprintf("#### %s\n\n", title))
+
+		body := strings.TrimSpace(release.Body)
+		if body == "" {
+			body = "_No release notes._"
+		}
+		builder.WriteString(body + "\n\n")
+	}
+	return builder.String()
+}
+
+// --- Breaking Changes ---
+
+// BreakingKind tells what marked a release-note entry as relevant to upgrade planning
+type BreakingKind string
+
+const (
+	BreakingChange BreakingKind = "breaking"   // "BREAKING CHANGE: ..." or an item under a "Breaking Changes" heading
+	BreakingCommit BreakingKind = "commit"     // Conventional commit with a "!" marker, e.g. "feat(api)!: drop v1"
+	Removed        BreakingKind = "removed"    // Item under a Keep a Changelog "Removed" heading
+	Deprecated     BreakingKind = "deprecated" // Item under a Keep a Changelog "Deprecated" heading
+	MigrationGuide BreakingKind = "migration"  // Link to a migration or upgrade guide
+)
+
+// BreakingItem is one breaking change, removal, deprecation or migration guide found in the notes
+type BreakingItem struct {
+	Version string       `json:"version"` // Tag of the release that announced it
+	Kind    BreakingKind `json:"kind"`
+	Text    string       `json:"text,omitempty"`
+	URL     string       `json:"url,omitempty"` // Set for migration guides
+}
+
+var (
+	breakingMarker = regexp.MustCompile(`(?i)^\W*breaking[ -]changes?\W*:?\s*`)
+	bangCommit     = regexp.MustCompile(`^[a-zA-Z]+(?:\([^)]*\))?!:\s*\S`)
+	markdownLink   = regexp.MustCompile(`\[([^\]]+)\]\((https?://[^)\s]+)\)`)
+	bareLink       = regexp.MustCompile(`https?://[^\s)>\]]+`)
+	listBullet     = regexp.MustCompile(`^\s*(?:[-*+]|\d+\.)\s+`)
+)
+
+// Breaking: Scans the notes of every release for breaking-change markers, newest release first
+func Breaking(notes Notes) []BreakingItem {
+	var items []BreakingItem
+	for _, release := range notes.Releases {
+		items = append(items, breakingItems(release)...)
+	}
+	return items
+}
+
+// breakingItems: Reads a release body line by line; the last heading decides whether list items
+// below it are breaking changes, removals or deprecations
+func breakingItems(release ReleaseNote) []BreakingItem {
+	seen := make(map[string]bool)
+	var items []BreakingItem
+	add := func(kind BreakingKind, text, link string) {
+		text = strings.TrimSpace(strings.Trim(strings.TrimSpace(text), "*_"))
+		key := string(kind) + "|" + text + "|" + link
+		if (text == "" && link == "") || seen[key] {
+			return
+		}
+		seen[key] = true
+		items = append(items, BreakingItem{Version: release.Tag, Kind: kind, Text: text, URL: link})
+	}
+
+	var section BreakingKind
+	for _, line := range strings.Split(strings.ReplaceAll(release.Body, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		isItem := listBullet.MatchString(line)
+		text := listBullet.ReplaceAllString(trimmed, "")
+
+		for _, match := range markdownLink.FindAllStringSubmatch(line, -1) {
+			if isMigrationLink(match[1] + " " + match[2]) {
+				add(MigrationGuide, match[1], match[2])
+			}
+		}
+		if !markdownLink.MatchString(line) {
+			for _, link := range bareLink.FindAllString(line, -1) {
+				if isMigrationLink(link) {
+					add(MigrationGuide, "", link)
+				}
+			}
+		}
+
+		switch {
+		case strings.HasPrefix(trimmed, "#"):
+			section = headingSection(strings.TrimLeft(trimmed, "# "))
+		case breakingMarker.MatchString(text):
+			// "BREAKING CHANGE: ..." is an item by itself; a bare "**BREAKING CHANGES**" opens a section
+			if rest := breakingMarker.ReplaceAllString(text, ""); strings.Trim(rest, "*_: ") != "" {
+				add(BreakingChange, rest, "")
+			} else {
+				section = BreakingChange
+			}
+		case bangCommit.MatchString(text):
+			add(BreakingCommit, text, "")
+		case isItem && section != "":
+			add(section, text, "")
+		}
+	}
+	return items
+}
+
+// headingSection: Maps a changelog heading onto the kind of its items, or "" for other sections
+func headingSection(heading string) BreakingKind {
+	heading = strings.ToLower(heading)
+	switch {
+	case strings.Contains(heading, "breaking"):
+		return BreakingChange
+	case strings.HasPrefix(heading, "removed") || strings.HasPrefix(heading, "removal"):
+		return Removed
+	case strings.HasPrefix(heading, "deprecat"):
+		return Deprecated
+	}
+	return ""
+}
+
+func isMigrationLink(text string) bool {
+	text = strings.ToLower(text)
+	return strings.Contains(text, "migrat") || strings.Contains(text, "upgrade guide") || strings.Contains(text, "upgrading")
+}
+
+// Label is the report label of the kind
+func (k BreakingKind) Label() string {
+	switch k {
+	case BreakingChange, BreakingCommit:
+		return "💥 Breaking"
+	case Removed:
+		return "🗑️ Removed"
+	case Deprecated:
+		return "⚠️ Deprecated"
+	case MigrationGuide:
+		return "🧭 Migration guide"
+	}
+	return string(k)
+}
+
+// FormatBreaking: Renders the items as a Markdown list, e.g. "- `v2.0.0` 💥 Breaking: drop Node 14"
+func FormatBreaking(items []BreakingItem) string {
+	var builder strings.Builder
+	for _, item := range items {
+		text := item.Text
+		if item.URL != "" {
+			if text == "" {
+				text = item.URL
+			}
+			text = fmt.Sprintf("[%s](%s)", text, item.URL)
+		}
+		builder.WriteString(fmt.Sprintf("- `%s` %s: %s\n", item.Version, item.Kind.Label(), text))
+	}
+	return builder.String()
+}
@@ -0,0 +1,199 @@
+package changelog
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"Sbom/forge"
+	"Sbom/version"
+
+	"github.com/google/go-github/v62/github"
+)
+
+// releasePages: Serves the GitHub releases API, one page per entry (newest first), and counts
+// the pages requested
+func releasePages(t *testing.T, pages [][]string, requested *int) *forge.GitHub {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		page = max(page, 1)
+		if page > len(pages) {
+			http.NotFound(w, r)
+			return
+		}
+		*requested = max(*requested, page)
+		if page < len(pages) {
+			next := *r.URL
+			query := next.Query()
+			query.Set("page", strconv.Itoa(page+1))
+			next.RawQuery = query.Encode()
+			w.Header().Set("Link", fmt.Sprintf(`<http://%s%s>; rel="next"`, r.Host, next.RequestURI()))
+		}
+		var releases []map[string]string
+		for _, tag := range pages[page-1] {
+			releases = append(releases, map[string]string{"tag_name": tag, "body": "Notes of " + tag})
+		}
+		json.NewEncoder(w).Encode(releases)
+	}))
+	t.Cleanup(server.Close)
+
+	client := github.NewClient(nil)
+	client.BaseURL, _ = url.Parse(server.URL + "/")
+	return forge.NewGitHub(client)
+}
+
+func TestCollectPagesByVersion(t *testing.T) {
+	tests := []struct {
+		name      string
+		pages     [][]string
+		current   string
+		latest    string
+		want      []string
+		requested int
+	}{
+		{
+			name:      "stops at a page older than current",
+			pages:     [][]string{{"v1.2.0", "v1.1.0"}, {"v1.0.0", "v0.9.0"}, {"v0.8.0"}},
+			current:   "v1.0.0",
+			latest:    "v1.2.0",
+			want:      []string{"v1.2.0", "v1.1.0"},
+			requested: 2,
+		},
+		{
+			name:      "current is a backport published after a newer release",
+			pages:     [][]string{{"v2.1.0", "v1.9.5"}, {"v2.0.0", "v1.9.4"}, {"v1.9.3", "v1.9.2"}, {"v1.9.1"}},
+			current:   "v1.9.5",
+			latest:    "v2.1.0",
+			want:      []string{"v2.1.0", "v2.0.0"},
+			requested: 3,
+		},
+		{
+			name:      "older backport published after a newer release",
+			pages:     [][]string{{"v2.1.0", "v1.9.6"}, {"v2.0.1", "v2.0.0"}, {"v1.9.5"}},
+			current:   "v2.0.0",
+			latest:    "v2.1.0",
+			want:      []string{"v2.1.0", "v2.0.1"},
+			requested: 3,
+		},
+		{
+			name:      "nightlies do not hold the paging up",
+			pages:     [][]string{{"v1.1.0", "nightly"}, {"v1.0.0", "nightly-2"}, {"v0.9.0"}},
+			current:   "v1.0.0",
+			latest:    "v1.1.0",
+			want:      []string{"v1.1.0"},
+			requested: 2,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			requested := 0
+			source := releasePages(t, test.pages, &requested)
+			notes, err := Collect(source, forge.Repository{Host: "github.com", Owner: "acme", Name: "lib"}, version.SemVer, test.current, test.latest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var tags []string
+			for _, release := range notes.Releases {
+				tags = append(tags, release.Tag)
+			}
+			if strings.Join(tags, ",") != strings.Join(test.want, ",") {
+				t.Errorf("got %v, want %v", tags, test.want)
+			}
+			if requested != test.requested {
+				t.Errorf("requested %d pages, want %d", requested, test.requested)
+			}
+		})
+	}
+}
+
+func TestBreakingItems(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []BreakingItem
+	}{
+		{
+			name: "inline breaking change marker",
+			body: "BREAKING CHANGE: drop Node 14\n- **BREAKING CHANGE**: rename `init` to `setup`",
+			want: []BreakingItem{
+				{Version: "v2.0.0", Kind: BreakingChange, Text: "drop Node 14"},
+				{Version: "v2.0.0", Kind: BreakingChange, Text: "rename `init` to `setup`"},
+			},
+		},
+		{
+			name: "bold marker opens a section",
+			body: "**BREAKING CHANGES**\n\n- Renamed the `cache` option\n- Dropped Go 1.20\n\n### Features\n\n- Faster startup",
+			want: []BreakingItem{
+				{Version: "v2.0.0", Kind: BreakingChange, Text: "Renamed the `cache` option"},
+				{Version: "v2.0.0", Kind: BreakingChange, Text: "Dropped Go 1.20"},
+			},
+		},
+		{
+			name: "breaking changes heading",
+			body: "## ⚠ Breaking Changes\r\n* config files are YAML only\r\n## Fixes\r\n* typo",
+			want: []BreakingItem{{Version: "v2.0.0", Kind: BreakingChange, Text: "config files are YAML only"}},
+		},
+		{
+			name: "conventional commits with a bang",
+			body: "- feat(api)!: remove the v1 endpoints\n- fix!: reject empty names\n- feat(api): add v2 endpoints",
+			want: []BreakingItem{
+				{Version: "v2.0.0", Kind: BreakingCommit, Text: "feat(api)!: remove the v1 endpoints"},
+				{Version: "v2.0.0", Kind: BreakingCommit, Text: "fix!: reject empty names"},
+			},
+		},
+		{
+			name: "keep a changelog headings",
+			body: "### Added\n- `--json` flag\n### Removed\n- The `legacy` command\n### Deprecated\n- The `--old` flag\n### Fixed\n- Crash on empty input",
+			want: []BreakingItem{
+				{Version: "v2.0.0", Kind: Removed, Text: "The `legacy` command"},
+				{Version: "v2.0.0", Kind: Deprecated, Text: "The `--old` flag"},
+			},
+		},
+		{
+			name: "migration links",
+			body: "See the [migration guide](https://example.com/docs/v2) and the [docs](https://example.com/docs).\nNotes: https://example.com/upgrading-to-v2",
+			want: []BreakingItem{
+				{Version: "v2.0.0", Kind: MigrationGuide, Text: "migration guide", URL: "https://example.com/docs/v2"},
+				{Version: "v2.0.0", Kind: MigrationGuide, URL: "https://example.com/upgrading-to-v2"},
+			},
+		},
+		{
+			name: "repeated entries count once",
+			body: "BREAKING CHANGE: drop Node 14\n\nBREAKING CHANGE: drop Node 14",
+			want: []BreakingItem{{Version: "v2.0.0", Kind: BreakingChange, Text: "drop Node 14"}},
+		},
+		{
+			name: "nothing breaking",
+			body: "- fix: handle nil maps\n- Nothing breaking in this release",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := breakingItems(ReleaseNote{Tag: "v2.0.0", Body: test.body})
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestBreaking(t *testing.T) {
+	notes := Notes{Releases: []ReleaseNote{
+		{Tag: "v3.0.0", Body: "- feat!: drop the v1 API"},
+		{Tag: "v2.1.0", Body: "- fix: typo"},
+		{Tag: "v2.0.0", Body: "### Deprecated\n- The v1 API"},
+	}}
+	want := []BreakingItem{
+		{Version: "v3.0.0", Kind: BreakingCommit, Text: "feat!: drop the v1 API"},
+		{Version: "v2.0.0", Kind: Deprecated, Text: "The v1 API"},
+	}
+	if got := Breaking(notes); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,952 @@
+// Package container inventories the OS packages installed in saved container images.
+package container
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"Sbom/audit"
+)
+
+// --- Data Structures ---
+
+// DependencyInfo mirrors the component model of the rebar audit, extended with OS package details
+type DependencyInfo struct {
+	Name           string
+	CurrentVersion string
+	RepoURL        string
+	LatestVersion  string
+	UpdateNeeded   bool
+	Status         string
+	Ecosystem      string // deb, apk or rpm
+	Arch           string
+	SourcePackage  string
+}
+
+// DockerManifest is one entry of manifest.json in a `docker save` archive
+type DockerManifest struct {
+	Config   string   `json:"Config"`
+	RepoTags []string `json:"RepoTags"`
+	Layers   []string `json:"Layers"`
+}
+
+type OCIDescriptor struct {
+	MediaType string `json:"mediaType"`
+	Digest    string `json:"digest"`
+}
+
+type OCIIndex struct {
+	Manifests []OCIDescriptor `json:"manifests"`
+}
+
+type OCIManifest struct {
+	MediaType string          `json:"mediaType"`
+	Layers    []OCIDescriptor `json:"layers"`
+	Manifests []OCIDescriptor `json:"manifests"` // Set when the blob is itself an index
+}
+
+// imageFilesystem holds the package database files left after applying all layers
+type imageFilesystem struct {
+	Files map[string][]byte
+}
+
+var packageDatabases = map[string]string{
+	"var/lib/dpkg/status":               "deb",
+	"lib/apk/db/installed":              "apk",
+	"var/lib/rpm/Packages":              "rpm",
+	"var/lib/rpm/rpmdb.sqlite":          "rpm",
+	"usr/lib/sysimage/rpm/Packages":     "rpm",
+	"usr/lib/sysimage/rpm/rpmdb.sqlite": "rpm",
+	"etc/os-release":                    "os",
+	"usr/lib/os-release":                "os",
+}
+
+// --- Image Archive Reading ---
+
+// isPackageDatabase: Reports whether a layer file is one of the databases we extract.
+// Distroless images keep one dpkg status file per package under status.d/.
+func isPackageDatabase(name string) bool {
+	if _, ok := packageDatabases[name]; ok {
+		return true
+	}
+	return strings.HasPrefix(name, "var/lib/dpkg/status.d/") && !strings.HasSuffix(name, ".md5sums")
+}
+
+// readSmallEntries: First pass over the archive, collecting manifests and JSON blobs
+func readSmallEntries(file *os.File) (map[string][]byte, error) {
+	entries := make(map[string][]byte)
+	reader := tar.NewReader(file)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading image archive: %w", err)
+		}
+		name := path.Clean(header.Name)
+		if header.Typeflag != tar.TypeReg || header.Size > 1<<20 {
+			continue
+		}
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			return nil, err
+		}
+		entries[name] = data
+	}
+	return entries, nil
+}
+
+// resolveLayerOrder: Returns layer paths (inside the archive) from the base layer upwards,
+// supporting both `docker save` and OCI image layout archives.
+func resolveLayerOrder(entries map[string][]byte) ([]string, string, error) {
+	if data, ok := entries["manifest.json"]; ok {
+		var manifests []DockerManifest
+		if err := json.Unmarshal(data, &manifests); err == nil && len(manifests) > 0 {
+			name := strings.Join(manifests[0].RepoTags, ", ")
+			var layers []string
+			for _, layer := range manifests[0].Layers {
+				layers = append(layers, path.Clean(layer))
+			}
+			return layers, name, nil
+		}
+	}
+
+	data, ok := entries["index.json"]
+	if !ok {
+		return nil, "", fmt.Errorf("neither manifest.json nor index.json found; not a docker save or OCI layout archive")
+	}
+	var index OCIIndex
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, "", fmt.Errorf("error parsing index.json: %w", err)
+	}
+
+	// Follow nested indexes (multi-platform images) down to the first image manifest
+	descriptors := index.Manifests
+	for depth := 0; depth < 5 && len(descriptors) > 0; depth++ {
+		blob, ok := entries[blobPath(descriptors[0].Digest)]
+		if !ok {
+			return nil, "", fmt.Errorf("manifest blob %s missing from archive", descriptors[0].Digest)
+		}
+		var manifest OCIManifest
+		if err := json.Unmarshal(blob, &manifest); err != nil {
+			return nil, "", fmt.Errorf("error parsing manifest %s: %w", descriptors[0].Digest, err)
+		}
+		if len(manifest.Manifests) > 0 {
+			descriptors = manifest.Manifests
+			continue
+		}
+		var layers []string
+		for _, layer := range manifest.Layers {
+			layers = append(layers, blobPath(layer.Digest))
+		}
+		return layers, descriptors[0].Digest, nil
+	}
+	return nil, "", fmt.Errorf("no image manifest found in index.json")
+}
+
+func blobPath(digest string) string {
+	algorithm, hex, _ := strings.Cut(digest, ":")
+	return path.Join("blobs", algorithm, hex)
+}
+
+// layerChanges is what a single layer adds to, or removes from, the package databases
+type layerChanges struct {
+	Files     map[string][]byte
+	Whiteouts []string
+}
+
+// readLayer: Extracts package database files and whiteouts from one (possibly gzipped) layer
+func readLayer(r io.Reader) (layerChanges, error) {
+	changes := layerChanges{Files: make(map[string][]byte)}
+
+	buffered := bufio.NewReader(r)
+	if magic, err := buffered.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(buffered)
+		if err != nil {
+			return changes, err
+		}
+		defer gz.Close()
+		r = gz
+	} else {
+		r = buffered
+	}
+
+	reader := tar.NewReader(r)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return changes, err
+		}
+		name := strings.TrimPrefix(path.Clean(header.Name), "/")
+		base := path.Base(name)
+
+		if strings.HasPrefix(base, ".wh.") {
+			// .wh.<name> removes a file from lower layers; .wh..wh..opq empties the directory
+			dir := path.Dir(name)
+			if base == ".wh..wh..opq" {
+				changes.Whiteouts = append(changes.Whiteouts, dir+"/")
+			} else {
+				changes.Whiteouts = append(changes.Whiteouts, path.Join(dir, strings.TrimPrefix(base, ".wh.")))
+			}
+			continue
+		}
+		if header.Typeflag != tar.TypeReg || !isPackageDatabase(name) {
+			continue
+		}
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			return changes, err
+		}
+		changes.Files[name] = data
+	}
+	return changes, nil
+}
+
+// readImage: Applies every layer in order and returns the resulting package database files
+func readImage(filename string) (imageFilesystem, string, error) {
+	fs := imageFilesystem{Files: make(map[string][]byte)}
+
+	file, err := os.Open(filename)
+	if err != nil {
+		return fs, "", fmt.Errorf("error opening image archive: %w", err)
+	}
+	defer file.Close()
+
+	entries, err := readSmallEntries(file)
+	if err != nil {
+		return fs, "", err
+	}
+	layers, imageName, err := resolveLayerOrder(entries)
+	if err != nil {
+		return fs, "", err
+	}
+
+	// Second pass: layers can be large, so they are streamed and only database files are kept
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return fs, "", err
+	}
+	wanted := make(map[string]bool)
+	for _, layer := range layers {
+		wanted[layer] = true
+	}
+	changesByLayer := make(map[string]layerChanges)
+	reader := tar.NewReader(file)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fs, "", fmt.Errorf("error reading image archive: %w", err)
+		}
+		name := path.Clean(header.Name)
+		if !wanted[name] {
+			continue
+		}
+		changes, err := readLayer(reader)
+		if err != nil {
+			return fs, "", fmt.Errorf("error reading layer %s: %w", name, err)
+		}
+		changesByLayer[name] = changes
+	}
+
+	for _, layer := range layers {
+		changes, ok := changesByLayer[layer]
+		if !ok {
+			return fs, "", fmt.Errorf("layer %s missing from archive", layer)
+		}
+		for _, whiteout := range changes.Whiteouts {
+			for existing := range fs.Files {
+				if existing == whiteout || (strings.HasSuffix(whiteout, "/") && strings.HasPrefix(existing, whiteout)) {
+					delete(fs.Files, existing)
+				}
+			}
+		}
+		for name, data := range changes.Files {
+			fs.Files[name] = data
+		}
+	}
+	return fs, imageName, nil
+}
+
+// --- Package Database Parsing ---
+
+// parseDpkgStatus: Reads installed packages from dpkg's RFC 822-style status file
+func parseDpkgStatus(data []byte) []DependencyInfo {
+	var pkgs []DependencyInfo
+	for _, stanza := range strings.Split(string(data), "\n\n") {
+		fields := make(map[string]string)
+		for _, line := range strings.Split(stanza, "\n") {
+			if strings.HasPrefix(line, " ") {
+				continue // Continuation lines (Description, Conffiles)
+			}
+			if key, value, found := strings.Cut(line, ":"); found {
+				fields[key] = strings.TrimSpace(value)
+			}
+		}
+		if fields["Package"] == "" {
+			continue
+		}
+		// Distroless status.d files have no Status field; everything listed there is installed
+		if status, ok := fields["Status"]; ok && !strings.HasSuffix(status, " installed") {
+			continue
+		}
+		source := strings.Fields(fields["Source"] + " ")
+		pkg := DependencyInfo{
+			Name:           fields["Package"],
+			CurrentVersion: fields["Version"],
+			Arch:           fields["Architecture"],
+			Ecosystem:      "deb",
+		}
+		if len(source) > 0 {
+			pkg.SourcePackage = source[0]
+		}
+		pkgs = append(pkgs, pkg)
+	}
+	return pkgs
+}
+
+// parseApkInstalled: Reads Alpine's installed database (single-letter keys, blank-line separated)
+func parseApkInstalled(data []byte) []DependencyInfo {
+	var pkgs []DependencyInfo
+	current := DependencyInfo{Ecosystem: "apk"}
+	flush := func() {
+		if current.Name != "" {
+			pkgs = append(pkgs, current)
+		}
+		current = DependencyInfo{Ecosystem: "apk"}
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			flush()
+			continue
+		}
+		if len(line) < 2 || line[1] != ':' {
+			continue
+		}
+		value := line[2:]
+		switch line[0] {
+		case 'P':
+			current.Name = value
+		case 'V':
+			current.CurrentVersion = value
+		case 'A':
+			current.Arch = value
+		case 'o':
+			current.SourcePackage = value
+		case 'U':
+			current.RepoURL = value
+		}
+	}
+	flush()
+	return pkgs
+}
+
+// parseOSRelease: Returns PRETTY_NAME from /etc/os-release
+func parseOSRelease(data []byte) string {
+	for _, line := range strings.Split(string(data), "\n") {
+		if value, found := strings.CutPrefix(line, "PRETTY_NAME="); found {
+			return strings.Trim(value, `"`)
+		}
+	}
+	return ""
+}
+
+// --- RPM Database Parsing ---
+
+const (
+	rpmTagName      = 1000
+	rpmTagVersion   = 1001
+	rpmTagRelease   = 1002
+	rpmTagEpoch     = 1003
+	rpmTagArch      = 1022
+	rpmTagSourceRPM = 1044
+
+	rpmTypeInt32  = 4
+	rpmTypeString = 6
+)
+
+// parseRPMHeader: Decodes the name/version fields of one RPM header blob as stored in rpmdb
+func parseRPMHeader(blob []byte) (DependencyInfo, error) {
+	pkg := DependencyInfo{Ecosystem: "rpm"}
+	if len(blob) < 8 {
+		return pkg, fmt.Errorf("header blob too short")
+	}
+	indexCount := int(binary.BigEndian.Uint32(blob[0:4]))
+	dataLength := int(binary.BigEndian.Uint32(blob[4:8]))
+	dataStart := 8 + indexCount*16
+	if indexCount <= 0 || dataStart+dataLength > len(blob) {
+		return pkg, fmt.Errorf("invalid header blob (il=%d, dl=%d)", indexCount, dataLength)
+	}
+	store := blob[dataStart : dataStart+dataLength]
+
+	readString := func(offset int) string {
+		if offset < 0 || offset >= len(store) {
+			return ""
+		}
+		end := bytes.IndexByte(store[offset:], 0)
+		if end == -1 {
+			return ""
+		}
+		return string(store[offset : offset+end])
+	}
+
+	var version, release, epoch string
+	for i := 0; i < indexCount; i++ {
+		entry := blob[8+i*16 : 8+(i+1)*16]
+		tag := binary.BigEndian.Uint32(entry[0:4])
+		kind := binary.BigEndian.Uint32(entry[4:8])
+		offset := int(int32(binary.BigEndian.Uint32(entry[8:12])))
+
+		switch tag {
+		case rpmTagName:
+			pkg.Name = readString(offset)
+		case rpmTagVersion:
+			version = readString(offset)
+		case rpmTagRelease:
+			release = readString(offset)
+		case rpmTagArch:
+			pkg.Arch = readString(offset)
+		case rpmTagSourceRPM:
+			pkg.SourcePackage = readString(offset)
+		case rpmTagEpoch:
+			if kind == rpmTypeInt32 && offset >= 0 && offset+4 <= len(store) {
+				epoch = fmt.Sprint(binary.BigEndian.Uint32(store[offset : offset+4]))
+			}
+		}
+	}
+
+	pkg.CurrentVersion = version
+	if release != "" {
+		pkg.CurrentVersion += "-" + release
+	}
+	if epoch != "" && epoch != "0" {
+		pkg.CurrentVersion = epoch + ":" + pkg.CurrentVersion
+	}
+	if pkg.Name == "" {
+		return pkg, fmt.Errorf("header has no name tag")
+	}
+	return pkg, nil
+}
+
+// parseRPMDatabase: Dispatches on the database format (SQLite for RHEL 9+/Fedora,
+// Berkeley DB hash for older releases) and decodes each stored header.
+func parseRPMDatabase(data []byte) ([]DependencyInfo, error) {
+	var blobs [][]byte
+	var err error
+	if bytes.HasPrefix(data, []byte("SQLite format 3\x00")) {
+		blobs, err = readSQLiteBlobs(data, "Packages")
+	} else {
+		blobs, err = readBerkeleyHashValues(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var pkgs []DependencyInfo
+	for _, blob := range blobs {
+		pkg, err := parseRPMHeader(blob)
+		if err != nil {
+			continue
+		}
+		// gpg-pubkey entries are imported signing keys, not installed software
+		if pkg.Name == "gpg-pubkey" {
+			continue
+		}
+		pkgs = append(pkgs, pkg)
+	}
+	return pkgs, nil
+}
+
+// readSQLiteVarint: Decodes SQLite's big-endian base-128 varint (up to 9 bytes)
+func readSQLiteVarint(b []byte) (uint64, int) {
+	var v uint64
+	for i := 0; i < 9 && i < len(b); i++ {
+		if i == 8 {
+			return v<<8 | uint64(b[i]), 9
+		}
+		v = v<<7 | uint64(b[i]&0x7f)
+		if b[i]&0x80 == 0 {
+			return v, i + 1
+		}
+	}
+	return v, len(b)
+}
+
+type sqliteReader struct {
+	data     []byte
+	pageSize int
+	usable   int
+}
+
+func (s *sqliteReader) page(n int) []byte {
+	start := (n - 1) * s.pageSize
+	if n < 1 || start+s.pageSize > len(s.data) {
+		return nil
+	}
+	return s.data[start : start+s.pageSize]
+}
+
+// payload: Returns a leaf cell's full record, following overflow pages when needed
+func (s *sqliteReader) payload(cell []byte) ([]byte, error) {
+	size, n := readSQLiteVarint(cell)
+	_, m := readSQLiteVarint(cell[n:]) // rowid
+	body := cell[n+m:]
+	// A record never holds more bytes than the database itself
+	if size > uint64(len(s.data)) {
+		return nil, fmt.Errorf("sqlite record of %d bytes exceeds the database size", size)
+	}
+	total := int(size)
+
+	maxLocal := s.usable - 35
+	if total <= maxLocal {
+		if total > len(body) {
+			return nil, fmt.Errorf("sqlite record exceeds its page")
+		}
+		return body[:total], nil
+	}
+	minLocal := (s.usable-12)*32/255 - 23
+	local := minLocal + (total-minLocal)%(s.usable-4)
+	if local > maxLocal {
+		local = minLocal
+	}
+	if local+4 > len(body) {
+		return nil, fmt.Errorf("sqlite record exceeds its page")
+	}
+
+	record := append([]byte{}, body[:local]...)
+	next := int(binary.BigEndian.Uint32(body[local : local+4]))
+	visited := make(map[int]bool)
+	for next != 0 && len(record) < total {
+		page := s.page(next)
+		if page == nil {
+			return nil, fmt.Errorf("sqlite overflow page %d out of range", next)
+		}
+		if visited[next] {
+			return nil, fmt.Errorf("sqlite overflow page %d is linked twice", next)
+		}
+		visited[next] = true
+		next = int(binary.BigEndian.Uint32(page[0:4]))
+		chunk := page[4:s.usable]
+		if remaining := total - len(record); remaining < len(chunk) {
+			chunk = chunk[:remaining]
+		}
+		record = append(record, chunk...)
+	}
+	if len(record) < total {
+		return nil, fmt.Errorf("sqlite overflow chain ends before the record does")
+	}
+	return record, nil
+}
+
+// walkTable: Visits every record of a table b-tree rooted at the given page. Each page is
+// visited once, so a corrupt tree that links back to itself cannot loop.
+func (s *sqliteReader) walkTable(root int, visit func(record []byte)) error {
+	visited := make(map[int]bool)
+	var walk func(n int) error
+	walk = func(n int) error {
+		page := s.page(n)
+		if page == nil {
+			return fmt.Errorf("sqlite page %d out of range", n)
+		}
+		if visited[n] {
+			return fmt.Errorf("sqlite page %d is linked twice", n)
+		}
+		visited[n] = true
+
+		headerStart := 0
+		if n == 1 {
+			headerStart = 100 // The database header precedes page 1's b-tree header
+		}
+		header := page[headerStart:]
+		cells := int(binary.BigEndian.Uint16(header[3:5]))
+
+		// cellPointer: The offset of cell i, after the b-tree header of the given size
+		cellPointer := func(headerSize, i int) (int, error) {
+			at := headerSize + i*2
+			if at+2 > len(header) {
+				return 0, fmt.Errorf("sqlite page %d: cell pointer %d out of range", n, i)
+			}
+			ptr := int(binary.BigEndian.Uint16(header[at:]))
+			if ptr >= len(page) {
+				return 0, fmt.Errorf("sqlite page %d: cell %d out of range", n, i)
+			}
+			return ptr, nil
+		}
+
+		switch header[0] {
+		case 0x0d: // Table leaf
+			for i := 0; i < cells; i++ {
+				ptr, err := cellPointer(8, i)
+				if err != nil {
+					return err
+				}
+				record, err := s.payload(page[ptr:])
+				if err != nil {
+					return fmt.Errorf("sqlite page %d, cell %d: %w", n, i, err)
+				}
+				visit(record)
+			}
+		case 0x05: // Table interior
+			for i := 0; i < cells; i++ {
+				ptr, err := cellPointer(12, i)
+				if err != nil {
+					return err
+				}
+				if ptr+4 > len(page) {
+					return fmt.Errorf("sqlite page %d: cell %d out of range", n, i)
+				}
+				if err := walk(int(binary.BigEndian.Uint32(page[ptr : ptr+4]))); err != nil {
+					return err
+				}
+			}
+			return walk(int(binary.BigEndian.Uint32(header[8:12])))
+		default:
+			return fmt.Errorf("sqlite page %d is not a table b-tree page", n)
+		}
+		return nil
+	}
+	return walk(root)
+}
+
+// recordColumns: Splits a SQLite record into its column values (integers as decimal text)
+func recordColumns(record []byte) [][]byte {
+	headerSize, n := readSQLiteVarint(record)
+	if headerSize > uint64(len(record)) {
+		return nil
+	}
+	var types []uint64
+	for pos := n; pos < int(headerSize); {
+		t, m := readSQLiteVarint(record[pos:])
+		types = append(types, t)
+		pos += m
+	}
+
+	var columns [][]byte
+	pos := int(headerSize)
+	intSizes := map[uint64]int{1: 1, 2: 2, 3: 3, 4: 4, 5: 6, 6: 8, 7: 8}
+	for _, t := range types {
+		size := 0
+		switch {
+		case t >= 12:
+			if (t-12)/2 > uint64(len(record)) {
+				return nil
+			}
+			size = int(t-12) / 2
+		default:
+			size = intSizes[t]
+		}
+		if pos+size > len(record) {
+			return nil
+		}
+		value := record[pos : pos+size]
+		if t >= 1 && t <= 6 {
+			var v int64
+			for _, b := range value {
+				v = v<<8 | int64(b)
+			}
+			value = []byte(fmt.Sprint(v))
+		}
+		columns = append(columns, value)
+		pos += size
+	}
+	return columns
+}
+
+// readSQLiteBlobs: Returns the blob column of every row in the named table
+func readSQLiteBlobs(data []byte, table string) ([][]byte, error) {
+	if len(data) < 100 {
+		return nil, fmt.Errorf("sqlite database too short")
+	}
+	reader := &sqliteReader{data: data, pageSize: int(binary.BigEndian.Uint16(data[16:18]))}
+	if reader.pageSize == 1 {
+		reader.pageSize = 65536
+	}
+	if !validPageSize(reader.pageSize) {
+		return nil, fmt.Errorf("invalid sqlite page size %d", reader.pageSize)
+	}
+	// SQLite requires at least 480 usable bytes per page
+	reader.usable = reader.pageSize - int(data[20])
+	if reader.usable < 480 {
+		return nil, fmt.Errorf("invalid sqlite reserved space %d", data[20])
+	}
+
+	// sqlite_master (page 1) columns: type, name, tbl_name, rootpage, sql
+	rootPage := 0
+	err := reader.walkTable(1, func(record []byte) {
+		cols := recordColumns(record)
+		if len(cols) >= 4 && string(cols[0]) == "table" && string(cols[1]) == table {
+			fmt.Sscan(string(cols[3]), &rootPage)
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reading sqlite schema: %w", err)
+	}
+	if rootPage == 0 {
+		return nil, fmt.Errorf("table %s not found in sqlite database", table)
+	}
+
+	var blobs [][]byte
+	err = reader.walkTable(rootPage, func(record []byte) {
+		// Packages(hnum INTEGER PRIMARY KEY, blob BLOB): hnum is the rowid, the blob is column 1
+		cols := recordColumns(record)
+		if len(cols) >= 2 && len(cols[1]) > 0 {
+			blobs = append(blobs, cols[1])
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reading sqlite table %s: %w", table, err)
+	}
+	return blobs, nil
+}
+
+// validPageSize: Both SQLite and Berkeley DB use power-of-two pages of 512 bytes to 64 KiB
+func validPageSize(size int) bool {
+	return size >= 512 && size <= 65536 && size&(size-1) == 0
+}
+
+// readBerkeleyHashValues: Returns all values of a Berkeley DB hash database. rpmdb stores each
+// header as an off-page item whose bytes are chained through overflow pages.
+func readBerkeleyHashValues(data []byte) ([][]byte, error) {
+	const (
+		hashMagic      = 0x061561
+		pageTypeHash   = 13
+		pageTypeUnsort = 2
+		itemOffPage    = 3
+	)
+	if len(data) < 512 {
+		return nil, fmt.Errorf("unsupported rpm database format")
+	}
+
+	var order binary.ByteOrder = binary.LittleEndian
+	if binary.LittleEndian.Uint32(data[12:16]) != hashMagic {
+		order = binary.BigEndian
+		if binary.BigEndian.Uint32(data[12:16]) != hashMagic {
+			return nil, fmt.Errorf("unsupported rpm database format (not sqlite or Berkeley DB hash)")
+		}
+	}
+	pageSize := int(order.Uint32(data[20:24]))
+	if !validPageSize(pageSize) {
+		return nil, fmt.Errorf("invalid Berkeley DB page size %d", pageSize)
+	}
+	page := func(n int) []byte {
+		start := n * pageSize
+		if start+pageSize > len(data) {
+			return nil
+		}
+		return data[start : start+pageSize]
+	}
+
+	var values [][]byte
+	for n := 1; n*pageSize < len(data); n++ {
+		p := page(n)
+		if p == nil || (p[25] != pageTypeHash && p[25] != pageTypeUnsort) {
+			continue
+		}
+		entries := int(order.Uint16(p[20:22]))
+		// Entries alternate key, value; only values are rpm headers
+		for i := 1; i < entries; i += 2 {
+			if 26+i*2+2 > len(p) {
+				return nil, fmt.Errorf("Berkeley DB page %d: entry %d out of range", n, i)
+			}
+			offset := int(order.Uint16(p[26+i*2:]))
+			if offset+12 > len(p) || p[offset] != itemOffPage {
+				continue
+			}
+			next := int(order.Uint32(p[offset+4 : offset+8]))
+			total := int(order.Uint32(p[offset+8 : offset+12]))
+			if total > len(data) {
+				return nil, fmt.Errorf("Berkeley DB page %d: item of %d bytes exceeds the database size", n, total)
+			}
+
+			value := make([]byte, 0, total)
+			visited := make(map[int]bool)
+			for next != 0 && len(value) < total {
+				overflow := page(next)
+				if overflow == nil {
+					return nil, fmt.Errorf("Berkeley DB overflow page %d out of range", next)
+				}
+				if visited[next] {
+					return nil, fmt.Errorf("Berkeley DB overflow page %d is linked twice", next)
+				}
+				visited[next] = true
+				used := int(order.Uint16(overflow[22:24])) // hf_offset holds the bytes used
+				if 26+used > len(overflow) {
+					return nil, fmt.Errorf("Berkeley DB overflow page %d: %d bytes used exceed the page", next, used)
+				}
+				value = append(value, overflow[26:26+used]...)
+				next = int(order.Uint32(overflow[16:20]))
+			}
+			if len(value) == total {
+				values = append(values, value)
+			}
+		}
+	}
+	return values, nil
+}
+
+// --- Component Extraction ---
+
+func extractPackages(fs imageFilesystem) ([]DependencyInfo, string) {
+	var pkgs []DependencyInfo
+	osName := ""
+
+	names := make([]string, 0, len(fs.Files))
+	for name := range fs.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		data := fs.Files[name]
+		switch {
+		case packageDatabases[name] == "os":
+			if osName == "" {
+				osName = parseOSRelease(data)
+			}
+		case packageDatabases[name] == "deb" || strings.HasPrefix(name, "var/lib/dpkg/status.d/"):
+			pkgs = append(pkgs, parseDpkgStatus(data)...)
+		case packageDatabases[name] == "apk":
+			pkgs = append(pkgs, parseApkInstalled(data)...)
+		case packageDatabases[name] == "rpm":
+			rpms, err := parseRPMDatabase(data)
+			if err != nil {
+				fmt.Printf("⚠️ Warning: Could not read %s: %v\n", name, err)
+				continue
+			}
+			pkgs = append(pkgs, rpms...)
+		}
+	}
+
+	for i := range pkgs {
+		pkgs[i].LatestVersion = "N/A"
+		pkgs[i].Status = "📦 Installed"
+	}
+	sort.Slice(pkgs, func(i, j int) bool {
+		if pkgs[i].Ecosystem != pkgs[j].Ecosystem {
+			return pkgs[i].Ecosystem < pkgs[j].Ecosystem
+		}
+		return pkgs[i].Name < pkgs[j].Name
+	})
+	return pkgs, osName
+}
+
+// --- Output Function (Markdown Table) ---
+
+// reportTemplate lists the installed packages; its model is an ImageReport
+var reportTemplate = audit.Template{
+	Name: "container",
+	Text: `## 🐧 Container Image OS Package Inventory
+
+* Image: {{code .Results.Image}}
+{{if .Results.OS}}* Operating System: **{{.Results.OS}}**
+{{end}}* Installed packages: **{{len .Results.Packages}}**
+{{summary .Report}}| # | Package | Type | Status | Installed Version | Architecture | Source Package |
+| :---: | :--- | :---: | :---: | :---: | :---: | :--- |
+{{$findings := .Report.Findings}}{{range $i, $pkg := .Results.Packages}}| {{add $i 1}} | {{code .Name}} | {{.Ecosystem}} | {{with index $findings $i}}{{accepted .Status .}}{{end}} | {{code .CurrentVersion}} | {{.Arch}} | {{.SourcePackage}} |
+{{end}}`,
+}
+
+// ImageReport is the model of the container report: the image, its operating system and its installed packages
+type ImageReport struct {
+	Image    string
+	OS       string
+	Packages []DependencyInfo
+}
+
+// printReport: Renders the installed packages through reportTemplate
+func printReport(imageName, osName string, results []DependencyInfo, report *audit.Report, filename string) error {
+	data := audit.TemplateData{Report: report, Results: ImageReport{Image: imageName, OS: osName, Packages: results}}
+	return reportTemplate.Write(filename, data)
+}
+
+// toSources converts the installed packages into the ecosystem-neutral model, one source per package type
+func toSources(imageName string, pkgs []DependencyInfo, started time.Time) []audit.Source {
+	var sources []audit.Source
+	for _, pkg := range pkgs {
+		if len(sources) == 0 || sources[len(sources)-1].Ecosystem != pkg.Ecosystem {
+			sources = append(sources, audit.NewSource(imageName, pkg.Ecosystem, started, nil, nil))
+		}
+		last := &sources[len(sources)-1]
+		last.Findings = append(last.Findings, toFinding(pkg))
+	}
+	return sources
+}
+
+// toFinding converts an installed package into the ecosystem-neutral model
+func toFinding(pkg DependencyInfo) audit.Finding {
+	return audit.Finding{
+		Name:           pkg.Name,
+		CurrentVersion: pkg.CurrentVersion,
+		LatestVersion:  pkg.LatestVersion,
+		Status:         pkg.Status,
+		UpdateNeeded:   pkg.UpdateNeeded,
+	}
+}
+
+// Run inventories the image archive given as first argument
+func Run(args []string) {
+	const outputFilePath = "container/report.md"
+
+	if len(args) < 1 {
+		fmt.Println("Usage: sbom container <image.tar>  (from `docker save` or an OCI image layout archive)")
+		os.Exit(1)
+	}
+	imageArchive := args[0]
+
+	// 1. Apply the image layers and keep the package databases
+	report := audit.NewReport("container", imageArchive)
+	started := time.Now()
+	fs, imageName, err := readImage(imageArchive)
+	if err != nil {
+		fmt.Printf("Fatal Error: %v\n", err)
+		os.Exit(1)
+	}
+	if imageName == "" {
+		imageName = imageArchive
+	}
+
+	// 2. Extract installed OS packages
+	pkgs, osName := extractPackages(fs)
+	if len(pkgs) == 0 {
+		fmt.Println("No dpkg, apk or rpm package database found in the image.")
+		return
+	}
+	fmt.Printf("Found %d installed packages in %s (%s).\n", len(pkgs), imageName, osName)
+
+	// Packages are sorted by type, so each package database becomes one source
+	for _, source := range toSources(imageName, pkgs, started) {
+		report.Add(source)
+	}
+	report.Finish()
+
+	// 3. Write the final Markdown and JSON reports
+	err = printReport(imageName, osName, pkgs, report, outputFilePath)
+	if err == nil {
+		err = report.WriteFiles(outputFilePath)
+	}
+	if err != nil {
+		fmt.Printf("Fatal Error writing report: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("✅ Operation completed successfully. Results saved in %s.\n", audit.OutputFiles(reportTemplate.Name, outputFilePath))
+}
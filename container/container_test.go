@@ -0,0 +1,228 @@
+package container
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"strings"
+	"testing"
+)
+
+// rpmHeader: Builds a header blob with the string tags rpmdb stores per package
+func rpmHeader(name, version, release string) []byte {
+	tags := []struct {
+		tag   uint32
+		value string
+	}{{rpmTagName, name}, {rpmTagVersion, version}, {rpmTagRelease, release}, {rpmTagArch, "x86_64"}}
+
+	var index, store bytes.Buffer
+	for _, tag := range tags {
+		_ = binary.Write(&index, binary.BigEndian, [4]uint32{tag.tag, rpmTypeString, uint32(store.Len()), 1})
+		store.WriteString(tag.value + "\x00")
+	}
+	blob := binary.BigEndian.AppendUint32(nil, uint32(len(tags)))
+	blob = binary.BigEndian.AppendUint32(blob, uint32(store.Len()))
+	return append(append(blob, index.Bytes()...), store.Bytes()...)
+}
+
+// berkeleyHash: Builds a little-endian Berkeley DB hash database of 512-byte pages: the meta
+// page, one hash page with a key/value pair per header, then each header's overflow chain
+func berkeleyHash(headers ...[]byte) []byte {
+	const pageSize = 512
+	chunk := pageSize - 26
+	pages := [][]byte{make([]byte, pageSize), make([]byte, pageSize)}
+	binary.LittleEndian.PutUint32(pages[0][12:16], 0x061561)
+	binary.LittleEndian.PutUint32(pages[0][20:24], pageSize)
+
+	hash := pages[1]
+	hash[25] = 13
+	binary.LittleEndian.PutUint16(hash[20:22], uint16(len(headers)*2))
+	itemOffset := pageSize
+	for i, header := range headers {
+		// The key: a plain item holding the package number
+		itemOffset -= 8
+		hash[itemOffset] = 1
+		binary.LittleEndian.PutUint16(hash[26+i*4:], uint16(itemOffset))
+
+		// The value: an off-page item pointing at the first overflow page
+		itemOffset -= 12
+		hash[itemOffset] = 3
+		binary.LittleEndian.PutUint32(hash[itemOffset+4:], uint32(len(pages)))
+		binary.LittleEndian.PutUint32(hash[itemOffset+8:], uint32(len(header)))
+		binary.LittleEndian.PutUint16(hash[26+i*4+2:], uint16(itemOffset))
+
+		for start := 0; start < len(header); start += chunk {
+			end := min(start+chunk, len(header))
+			page := make([]byte, pageSize)
+			page[25] = 7
+			binary.LittleEndian.PutUint16(page[22:24], uint16(end-start))
+			if end < len(header) {
+				binary.LittleEndian.PutUint32(page[16:20], uint32(len(pages)+1))
+			}
+			copy(page[26:], header[start:end])
+			pages = append(pages, page)
+		}
+	}
+	return bytes.Join(pages, nil)
+}
+
+func TestParseRPMDatabaseSQLite(t *testing.T) {
+	// rpmdb.sqlite mirrors rpm's schema with 512-byte pages: gpg-pubkey, pkg-00 … pkg-39
+	// (1.N.0-1.el9) and bash 1:5.1.8-9.el9, whose 3 KB header spans overflow pages. With 42
+	// rows the Packages root is an interior page.
+	data, err := os.ReadFile("testdata/rpmdb.sqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkgs, err := parseRPMDatabase(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pkgs) != 41 {
+		t.Fatalf("got %d packages, want 41", len(pkgs))
+	}
+	versions := make(map[string]string)
+	for _, pkg := range pkgs {
+		versions[pkg.Name] = pkg.CurrentVersion
+	}
+	for name, want := range map[string]string{"pkg-00": "1.0.0-1.el9", "pkg-39": "1.39.0-1.el9", "bash": "1:5.1.8-9.el9"} {
+		if versions[name] != want {
+			t.Errorf("%s: got %q, want %q", name, versions[name], want)
+		}
+	}
+	if _, ok := versions["gpg-pubkey"]; ok {
+		t.Error("gpg-pubkey reported as a package")
+	}
+}
+
+func TestReadSQLiteBlobsCorrupt(t *testing.T) {
+	fixture, err := os.ReadFile("testdata/rpmdb.sqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	const pageSize = 512
+	// Page 2 is the Packages root, an interior page: header at 0, cell pointers from 12
+	packagesRoot := pageSize
+
+	tests := []struct {
+		name    string
+		corrupt func(data []byte) []byte
+		want    string
+	}{
+		{"page size not a power of two", func(data []byte) []byte {
+			binary.BigEndian.PutUint16(data[16:18], 1000)
+			return data
+		}, "page size"},
+		{"page size too small", func(data []byte) []byte {
+			binary.BigEndian.PutUint16(data[16:18], 256)
+			return data
+		}, "page size"},
+		{"too many cells for the page", func(data []byte) []byte {
+			binary.BigEndian.PutUint16(data[packagesRoot+3:], 0xffff)
+			return data
+		}, "out of range"},
+		{"cell pointer past the page", func(data []byte) []byte {
+			binary.BigEndian.PutUint16(data[packagesRoot+12:], pageSize-2)
+			return data
+		}, "out of range"},
+		{"child page out of range", func(data []byte) []byte {
+			binary.BigEndian.PutUint32(data[packagesRoot+8:], 0xffffff)
+			return data
+		}, "out of range"},
+		{"child links back to the root", func(data []byte) []byte {
+			binary.BigEndian.PutUint32(data[packagesRoot+8:], 2)
+			return data
+		}, "linked twice"},
+		{"truncated database", func(data []byte) []byte {
+			return data[:len(data)-pageSize]
+		}, "out of range"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data := test.corrupt(append([]byte{}, fixture...))
+			_, err := readSQLiteBlobs(data, "Packages")
+			if err == nil || !strings.Contains(err.Error(), test.want) {
+				t.Errorf("got error %v, want one containing %q", err, test.want)
+			}
+		})
+	}
+}
+
+func TestSQLitePayloadOverflowLoop(t *testing.T) {
+	// Page 2 links its overflow chain back to itself
+	data := make([]byte, 8*512)
+	reader := &sqliteReader{data: data, pageSize: 512, usable: 512}
+	binary.BigEndian.PutUint32(data[512:516], 2)
+
+	cell := []byte{0x83, 0x74, 0x01} // A 500-byte record, rowid 1: 39 bytes local, the rest on page 2
+	cell = append(cell, make([]byte, 1000)...)
+	local := (512-12)*32/255 - 23
+	binary.BigEndian.PutUint32(cell[3+local:], 2)
+	if _, err := reader.payload(cell[:3+local+4]); err != nil {
+		t.Fatalf("a record that fits the chain: %v", err)
+	}
+
+	cell[0], cell[1] = 0x90, 0x00 // 2048 bytes: more than one overflow page holds
+	if _, err := reader.payload(cell[:3+local+4]); err == nil || !strings.Contains(err.Error(), "linked twice") {
+		t.Errorf("got error %v, want a linked twice error", err)
+	}
+}
+
+func TestParseRPMDatabaseBerkeley(t *testing.T) {
+	headers := [][]byte{
+		rpmHeader("bash", "4.2.46", "34.el7"),
+		rpmHeader("glibc", "2.17", "326.el7_9"+strings.Repeat(".x", 400)),
+		rpmHeader("gpg-pubkey", "f4a80eb5", "53a7ff4b"),
+	}
+	pkgs, err := parseRPMDatabase(berkeleyHash(headers...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pkgs) != 2 || pkgs[0].Name != "bash" || pkgs[0].CurrentVersion != "4.2.46-34.el7" || pkgs[1].Name != "glibc" {
+		t.Errorf("got %+v, want bash 4.2.46-34.el7 and glibc", pkgs)
+	}
+}
+
+func TestReadBerkeleyHashValuesCorrupt(t *testing.T) {
+	const pageSize = 512
+	valueOffset := pageSize - 8 - 12 // The off-page item of the first header
+	tests := []struct {
+		name    string
+		corrupt func(data []byte)
+		want    string
+	}{
+		{"page size not a power of two", func(data []byte) {
+			binary.LittleEndian.PutUint32(data[20:24], 1000)
+		}, "page size"},
+		{"page size too large", func(data []byte) {
+			binary.LittleEndian.PutUint32(data[20:24], 1<<17)
+		}, "page size"},
+		{"too many entries for the page", func(data []byte) {
+			binary.LittleEndian.PutUint16(data[pageSize+20:], 0xffff)
+		}, "out of range"},
+		{"item larger than the database", func(data []byte) {
+			binary.LittleEndian.PutUint32(data[pageSize+valueOffset+8:], 0xffffffff)
+		}, "exceeds the database size"},
+		{"overflow page out of range", func(data []byte) {
+			binary.LittleEndian.PutUint32(data[pageSize+valueOffset+4:], 0xffffff)
+		}, "out of range"},
+		{"empty overflow page linked to itself", func(data []byte) {
+			overflow := data[2*pageSize : 3*pageSize]
+			binary.LittleEndian.PutUint16(overflow[22:24], 0)
+			binary.LittleEndian.PutUint32(overflow[16:20], 2)
+		}, "linked twice"},
+		{"used bytes past the page", func(data []byte) {
+			binary.LittleEndian.PutUint16(data[2*pageSize+22:], pageSize)
+		}, "exceed the page"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data := berkeleyHash(rpmHeader("bash", "4.2.46", "34.el7"))
+			test.corrupt(data)
+			_, err := readBerkeleyHashValues(data)
+			if err == nil || !strings.Contains(err.Error(), test.want) {
+				t.Errorf("got error %v, want one containing %q", err, test.want)
+			}
+		})
+	}
+}
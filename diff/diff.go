@@ -0,0 +1,552 @@
+// Package diff compares two scans — JSON reports written by this tool, or CycloneDX / SPDX JSON
+// SBOMs — and lists what a release changes: added, removed, upgraded and downgraded components,
+// newly vulnerable and newly archived dependencies.
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+
+	"Sbom/audit"
+	"Sbom/version"
+)
+
+// Component is one dependency of a scan, whichever format the scan was read from
+type Component struct {
+	Ecosystem  string
+	Name       string
+	Version    string
+	Source     string // Manifest the component was declared in; empty for SBOMs, which do not record it
+	Vulnerable bool
+	Archived   bool
+	Advisories []string
+}
+
+// key identifies a component across scans
+func (c Component) key() string {
+	return c.Ecosystem + "|" + c.Name
+}
+
+// Inventory is a loaded scan
+type Inventory struct {
+	Path       string
+	Format     string                 // "report", "CycloneDX" or "SPDX"
+	Components map[string][]Component // Every occurrence of a dependency (e.g. one per manifest), keyed by ecosystem and name
+}
+
+// Count is the number of components, counting a dependency once per manifest and version
+func (inv Inventory) Count() int {
+	count := 0
+	for _, occurrences := range inv.Components {
+		count += len(occurrences)
+	}
+	return count
+}
+
+// add: Records an occurrence of a component; one listed twice in the same manifest at the same
+// version is kept once, with the flags and advisories of both
+func (inv *Inventory) add(component Component) {
+	key := component.key()
+	for i, existing := range inv.Components[key] {
+		if existing.Source == component.Source && sameVersion(existing, component) {
+			existing.Vulnerable = existing.Vulnerable || component.Vulnerable
+			existing.Archived = existing.Archived || component.Archived
+			existing.Advisories = append(existing.Advisories, component.Advisories...)
+			inv.Components[key][i] = existing
+			return
+		}
+	}
+	inv.Components[key] = append(inv.Components[key], component)
+}
+
+// --- Loading ---
+
+// Load: Reads a scan, detecting its format from the document's top-level fields
+func Load(path string) (Inventory, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Inventory{}, fmt.Errorf("error reading scan: %w", err)
+	}
+
+	var probe struct {
+		SchemaVersion string `json:"schemaVersion"`
+		BOMFormat     string `json:"bomFormat"`
+		SPDXVersion   string `json:"spdxVersion"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return Inventory{}, fmt.Errorf("error parsing %s (only JSON scans are supported): %w", path, err)
+	}
+
+	inventory := Inventory{Path: path, Components: make(map[string][]Component)}
+	switch {
+	case probe.BOMFormat == "CycloneDX":
+		inventory.Format = "CycloneDX"
+		err = loadCycloneDX(data, &inventory)
+	case probe.SPDXVersion != "":
+		inventory.Format = "SPDX"
+		err = loadSPDX(data, &inventory)
+	case probe.SchemaVersion != "":
+		inventory.Format = "report"
+		err = loadReport(data, &inventory)
+	default:
+		return Inventory{}, fmt.Errorf("%s is neither a JSON report, a CycloneDX nor an SPDX document", path)
+	}
+	if err != nil {
+		return Inventory{}, fmt.Errorf("error parsing %s: %w", path, err)
+	}
+	return inventory, nil
+}
+
+// loadReport: Reads the JSON report written next to every Markdown report
+func loadReport(data []byte, inventory *Inventory) error {
+	var report audit.Report
+	if err := json.Unmarshal(data, &report); err != nil {
+		return err
+	}
+	for _, source := range report.Sources {
+		for _, finding := range source.Findings {
+			manifest := finding.Manifest
+			if manifest == "" {
+				manifest = source.Path
+			}
+			inventory.add(Component{
+				Ecosystem:  source.Ecosystem,
+				Name:       finding.Name,
+				Version:    finding.CurrentVersion,
+				Source:     manifest,
+				Vulnerable: finding.Vulnerable,
+				Archived:   finding.Archived,
+				Advisories: finding.Advisories,
+			})
+		}
+	}
+	return nil
+}
+
+type cycloneDXComponent struct {
+	BOMRef     string               `json:"bom-ref"`
+	Group      string               `json:"group"`
+	Name       string               `json:"name"`
+	Version    string               `json:"version"`
+	PURL       string               `json:"purl"`
+	Components []cycloneDXComponent `json:"components"` // Sub-components, e.g. the modules of an assembly
+}
+
+type cycloneDXDocument struct {
+	Components      []cycloneDXComponent `json:"components"`
+	Vulnerabilities []struct {
+		ID      string `json:"id"`
+		Affects []struct {
+			Ref string `json:"ref"`
+		} `json:"affects"`
+	} `json:"vulnerabilities"`
+}
+
+// loadCycloneDX: Reads the components of a CycloneDX BOM; those its vulnerabilities affect are vulnerable
+func loadCycloneDX(data []byte, inventory *Inventory) error {
+	var document cycloneDXDocument
+	if err := json.Unmarshal(data, &document); err != nil {
+		return err
+	}
+
+	advisories := make(map[string][]string)
+	for _, vulnerability := range document.Vulnerabilities {
+		for _, affected := range vulnerability.Affects {
+			advisories[affected.Ref] = append(advisories[affected.Ref], vulnerability.ID)
+		}
+	}
+
+	var walk func([]cycloneDXComponent)
+	walk = func(components []cycloneDXComponent) {
+		for _, c := range components {
+			component := fromPURL(c.PURL)
+			if component.Name == "" {
+				component.Name = c.Name
+				if c.Group != "" {
+					component.Name = c.Group + "/" + c.Name
+				}
+			}
+			if c.Version != "" {
+				component.Version = c.Version
+			}
+			component.Advisories = advisories[c.BOMRef]
+			component.Vulnerable = len(component.Advisories) > 0
+			inventory.add(component)
+			walk(c.Components)
+		}
+	}
+	walk(document.Components)
+	return nil
+}
+
+type spdxDocument struct {
+	DocumentDescribes []string `json:"documentDescribes"`
+	Packages          []struct {
+		SPDXID       string `json:"SPDXID"`
+		Name         string `json:"name"`
+		VersionInfo  string `json:"versionInfo"`
+		ExternalRefs []struct {
+			ReferenceType    string `json:"referenceType"`
+			ReferenceLocator string `json:"referenceLocator"`
+		} `json:"externalRefs"`
+	} `json:"packages"`
+}
+
+// loadSPDX: Reads the packages of an SPDX document, except the ones the document describes
+// (the scanned project itself). "advisory" references mark a package vulnerable.
+func loadSPDX(data []byte, inventory *Inventory) error {
+	var document spdxDocument
+	if err := json.Unmarshal(data, &document); err != nil {
+		return err
+	}
+
+	described := make(map[string]bool)
+	for _, id := range document.DocumentDescribes {
+		described[id] = true
+	}
+	for _, pkg := range document.Packages {
+		if described[pkg.SPDXID] {
+			continue
+		}
+		component := Component{Name: pkg.Name}
+		for _, ref := range pkg.ExternalRefs {
+			switch ref.ReferenceType {
+			case "purl":
+				if parsed := fromPURL(ref.ReferenceLocator); parsed.Name != "" {
+					component.Ecosystem, component.Name = parsed.Ecosystem, parsed.Name
+				}
+			case "advisory":
+				component.Advisories = append(component.Advisories, ref.ReferenceLocator)
+			}
+		}
+		component.Version = pkg.VersionInfo
+		component.Vulnerable = len(component.Advisories) > 0
+		inventory.add(component)
+	}
+	return nil
+}
+
+// purlEcosystems maps package URL types onto the ecosystem names of the reports
+var purlEcosystems = map[string]string{
+	"golang": "go",
+	"hex":    "rebar",
+	"github": "github-actions",
+	"oci":    "docker",
+}
+
+// fromPURL: Splits a package URL (pkg:type/namespace/name@version?qualifiers#subpath) into a
+// component named as the reports name it: "group:artifact" for Maven, "namespace/name" otherwise
+func fromPURL(purl string) Component {
+	rest, ok := strings.CutPrefix(purl, "pkg:")
+	if !ok {
+		return Component{}
+	}
+	rest, _, _ = strings.Cut(rest, "#")
+	rest, _, _ = strings.Cut(rest, "?")
+
+	var component Component
+	if at := strings.LastIndex(rest, "@"); at > strings.LastIndex(rest, "/") {
+		component.Version, _ = url.PathUnescape(rest[at+1:])
+		rest = rest[:at]
+	}
+
+	segments := strings.Split(rest, "/")
+	if len(segments) < 2 {
+		return Component{}
+	}
+	component.Ecosystem = strings.ToLower(segments[0])
+	if mapped, ok := purlEcosystems[component.Ecosystem]; ok {
+		component.Ecosystem = mapped
+	}
+	for i, segment := range segments {
+		segments[i], _ = url.PathUnescape(segment)
+	}
+
+	separator := "/"
+	if component.Ecosystem == "maven" {
+		separator = ":"
+	}
+	component.Name = strings.Join(segments[1:], separator)
+	return component
+}
+
+// --- Comparison ---
+
+// ChangeKind tells how a component differs between the two scans
+type ChangeKind string
+
+const (
+	Added      ChangeKind = "added"
+	Removed    ChangeKind = "removed"
+	Upgraded   ChangeKind = "upgraded"
+	Downgraded ChangeKind = "downgraded"
+	Changed    ChangeKind = "changed" // The versions differ but cannot be ordered, e.g. a tag replaced by a digest
+)
+
+// Label is the report label of the kind
+func (k ChangeKind) Label() string {
+	switch k {
+	case Added:
+		return "➕ Added"
+	case Removed:
+		return "➖ Removed"
+	case Upgraded:
+		return "⬆️ Upgraded"
+	case Downgraded:
+		return "⬇️ Downgraded"
+	case Changed:
+		return "🔁 Changed"
+	}
+	return string(k)
+}
+
+// Change is one component that was added, removed or moved to another version
+type Change struct {
+	Kind ChangeKind
+	Old  Component // Zero for added components
+	New  Component // Zero for removed components
+	Bump version.Bump
+}
+
+// Component is the side of the change that exists in the newer scan, or the removed one
+func (c Change) Component() Component {
+	if c.Kind == Removed {
+		return c.Old
+	}
+	return c.New
+}
+
+// Result is everything that changed between two scans
+type Result struct {
+	Old             Inventory
+	New             Inventory
+	Changes         []Change    // Sorted by ecosystem and name
+	NewlyVulnerable []Component // Vulnerable now, but not in the old scan (or not present at all)
+	NewlyArchived   []Component
+	Unchanged       int
+}
+
+// Count returns the number of changes of a kind, e.g. {{.Results.Count "added"}} in templates
+func (r Result) Count(kind ChangeKind) int {
+	count := 0
+	for _, change := range r.Changes {
+		if change.Kind == kind {
+			count++
+		}
+	}
+	return count
+}
+
+// Compare: Matches the components of both scans by ecosystem and name, then pairs the
+// occurrences of each dependency (see pair)
+func Compare(before, after Inventory) Result {
+	result := Result{Old: before, New: after}
+
+	keys := make(map[string]bool)
+	for key := range before.Components {
+		keys[key] = true
+	}
+	for key := range after.Components {
+		keys[key] = true
+	}
+	for key := range keys {
+		previous, current := before.Components[key], after.Components[key]
+		result.pair(previous, current)
+
+		wasVulnerable, wasArchived := false, false
+		for _, component := range previous {
+			wasVulnerable = wasVulnerable || component.Vulnerable
+			wasArchived = wasArchived || component.Archived
+		}
+		for _, component := range current {
+			if component.Vulnerable && !wasVulnerable {
+				result.NewlyVulnerable = append(result.NewlyVulnerable, component)
+			}
+			if component.Archived && !wasArchived {
+				result.NewlyArchived = append(result.NewlyArchived, component)
+			}
+		}
+	}
+
+	sort.Slice(result.Changes, func(i, j int) bool {
+		return result.Changes[i].Component().less(result.Changes[j].Component())
+	})
+	byKey := func(components []Component) func(i, j int) bool {
+		return func(i, j int) bool { return components[i].less(components[j]) }
+	}
+	sort.Slice(result.NewlyVulnerable, byKey(result.NewlyVulnerable))
+	sort.Slice(result.NewlyArchived, byKey(result.NewlyArchived))
+	return result
+}
+
+// less orders components by ecosystem, name, manifest and version
+func (c Component) less(other Component) bool {
+	if c.key() != other.key() {
+		return c.key() < other.key()
+	}
+	if c.Source != other.Source {
+		return c.Source < other.Source
+	}
+	return c.Version < other.Version
+}
+
+// sameVersion: Tells whether two occurrences of a dependency are at the same version under its
+// scheme, so a report's v18.2.0 matches an SBOM's 18.2.0, and 1.0 matches 1.0.0
+func sameVersion(previous, current Component) bool {
+	if previous.Version == current.Version {
+		return true
+	}
+	scheme := version.For(current.Ecosystem, current.Name)
+	return scheme.Valid(previous.Version) && scheme.Valid(current.Version) && scheme.Compare(previous.Version, current.Version) == 0
+}
+
+// pairRules match an old occurrence of a dependency to a new one, most specific first: the same
+// manifest at the same version, the same manifest, the same version (a moved manifest), then
+// whatever is left in manifest order
+var pairRules = []func(previous, current Component) bool{
+	func(previous, current Component) bool {
+		return previous.Source == current.Source && sameVersion(previous, current)
+	},
+	func(previous, current Component) bool {
+		return previous.Source != "" && previous.Source == current.Source
+	},
+	sameVersion,
+	func(previous, current Component) bool { return true },
+}
+
+// pair: Matches the occurrences of one dependency in both scans, so upgrading one of two copies
+// is a change, and records the unmatched ones as added or removed
+func (r *Result) pair(previous, current []Component) {
+	previous = sortedComponents(previous)
+	current = sortedComponents(current)
+	matchedOld := make([]bool, len(previous))
+	matchedNew := make([]bool, len(current))
+
+	for _, matches := range pairRules {
+		for i, old := range previous {
+			if matchedOld[i] {
+				continue
+			}
+			for j, component := range current {
+				if matchedNew[j] || !matches(old, component) {
+					continue
+				}
+				matchedOld[i], matchedNew[j] = true, true
+				if sameVersion(old, component) {
+					r.Unchanged++
+				} else {
+					r.Changes = append(r.Changes, versionChange(old, component))
+				}
+				break
+			}
+		}
+	}
+
+	for j, component := range current {
+		if !matchedNew[j] {
+			r.Changes = append(r.Changes, Change{Kind: Added, New: component})
+		}
+	}
+	for i, old := range previous {
+		if !matchedOld[i] {
+			r.Changes = append(r.Changes, Change{Kind: Removed, Old: old})
+		}
+	}
+}
+
+// sortedComponents: A copy of the components in manifest and version order
+func sortedComponents(components []Component) []Component {
+	sorted := append([]Component(nil), components...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].less(sorted[j]) })
+	return sorted
+}
+
+// versionChange: Orders the two versions under the component's versioning scheme
+func versionChange(previous, current Component) Change {
+	change := Change{Kind: Changed, Old: previous, New: current}
+	scheme := version.For(current.Ecosystem, current.Name)
+	if !scheme.Valid(previous.Version) || !scheme.Valid(current.Version) {
+		return change
+	}
+	switch scheme.Compare(previous.Version, current.Version) {
+	case -1:
+		change.Kind = Upgraded
+		change.Bump = scheme.Classify(previous.Version, current.Version)
+	case 1:
+		change.Kind = Downgraded
+		change.Bump = scheme.Classify(current.Version, previous.Version)
+	}
+	return change
+}
+
+// --- Output Function (Markdown) ---
+
+// reportTemplate leads with the new risks, which are what a release review has to act on
+var reportTemplate = audit.Template{
+	Name: "diff",
+	Text: `# 🔀 Dependency Diff
+
+This report compares {{code .Results.Old.Path}} ({{.Results.Old.Format}}, **{{.Results.Old.Count}}** components) with {{code .Results.New.Path}} ({{.Results.New.Format}}, **{{.Results.New.Count}}** components).
+
+* ➕ Added: **{{.Results.Count "added"}}** · ➖ Removed: **{{.Results.Count "removed"}}** · ⬆️ Upgraded: **{{.Results.Count "upgraded"}}** · ⬇️ Downgraded: **{{.Results.Count "downgraded"}}** · 🔁 Changed: **{{.Results.Count "changed"}}** · Unchanged: {{.Results.Unchanged}}
+* 🚨 Newly vulnerable: **{{len .Results.NewlyVulnerable}}** · ⛔️ Newly archived: **{{len .Results.NewlyArchived}}**
+
+## 🚨 Newly Vulnerable Dependencies
+
+{{if .Results.NewlyVulnerable}}| # | Dependency | Ecosystem | Version | Manifest | Advisories |
+| :---: | :--- | :---: | :---: | :--- | :--- |
+{{range $i, $c := .Results.NewlyVulnerable}}| {{add $i 1}} | {{code .Name}} | {{.Ecosystem}} | {{code .Version}} | {{template "manifest" .}} | {{cell (join .Advisories ", ")}} |
+{{end}}{{else}}_None._
+{{end}}
+## ⛔️ Newly Archived Dependencies
+
+{{if .Results.NewlyArchived}}| # | Dependency | Ecosystem | Version | Manifest |
+| :---: | :--- | :---: | :---: | :--- |
+{{range $i, $c := .Results.NewlyArchived}}| {{add $i 1}} | {{code .Name}} | {{.Ecosystem}} | {{code .Version}} | {{template "manifest" .}} |
+{{end}}{{else}}_None._
+{{end}}
+## 📦 Component Changes
+
+{{if .Results.Changes}}| # | Dependency | Ecosystem | Manifest | Change | Old Version | New Version |
+| :---: | :--- | :---: | :--- | :---: | :---: | :---: |
+{{range $i, $change := .Results.Changes}}{{with .Component}}| {{add $i 1}} | {{code .Name}} | {{.Ecosystem}} | {{template "manifest" .}} | {{end}}{{.Kind.Label}}{{if .Bump}} ({{.Bump}}){{end}} | {{if .Old.Version}}{{code .Old.Version}}{{else}}-{{end}} | {{if .New.Version}}{{code .New.Version}}{{else}}-{{end}} |
+{{end}}{{else}}_No component changed._
+{{end}}
+{{- define "manifest"}}{{if .Source}}{{code .Source}}{{else}}-{{end}}{{end}}`,
+}
+
+// WriteReport: Renders the result to filename (and any user templates next to it)
+func WriteReport(result Result, filename string) error {
+	return reportTemplate.Write(filename, audit.TemplateData{Results: result})
+}
+
+// OutputFiles names the files the diff writes to filename, for its completion message
+func OutputFiles(filename string) string {
+	return audit.FileList(append([]string{filename}, audit.TemplateOutputs(reportTemplate.Name, filename)...))
+}
+
+// Run compares the two scans given as arguments and writes the diff to filename
+func Run(args []string, filename string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("usage: sbom diff <old.json> <new.json> (JSON reports, CycloneDX or SPDX JSON)")
+	}
+
+	before, err := Load(args[0])
+	if err != nil {
+		return err
+	}
+	after, err := Load(args[1])
+	if err != nil {
+		return err
+	}
+
+	result := Compare(before, after)
+	fmt.Printf("🔀 %d added, %d removed, %d upgraded, %d downgraded, %d newly vulnerable, %d newly archived.\n",
+		result.Count(Added), result.Count(Removed), result.Count(Upgraded), result.Count(Downgraded),
+		len(result.NewlyVulnerable), len(result.NewlyArchived))
+	return WriteReport(result, filename)
+}
@@ -0,0 +1,163 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// inventory: Builds a scan from "source name@version" entries; a trailing "!" marks the component vulnerable
+func inventory(entries ...string) Inventory {
+	inv := Inventory{Components: make(map[string][]Component)}
+	for _, entry := range entries {
+		source, dependency, _ := strings.Cut(entry, " ")
+		vulnerable := strings.HasSuffix(dependency, "!")
+		name, version, _ := strings.Cut(strings.TrimSuffix(dependency, "!"), "@")
+		inv.add(Component{Ecosystem: "npm", Name: name, Version: version, Source: strings.TrimPrefix(source, "-"), Vulnerable: vulnerable})
+	}
+	return inv
+}
+
+// describe: Flattens the changes of a result, e.g. "upgraded lodash b/package.json 4.17.20→4.17.21"
+func describe(result Result) []string {
+	var changes []string
+	for _, change := range result.Changes {
+		component := change.Component()
+		changes = append(changes, fmt.Sprintf("%s %s %s %s→%s", change.Kind, component.Name, component.Source, change.Old.Version, change.New.Version))
+	}
+	return changes
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name       string
+		before     Inventory
+		after      Inventory
+		changes    []string
+		unchanged  int
+		vulnerable int
+	}{
+		{
+			name:      "one of two copies upgraded",
+			before:    inventory("a/package.json lodash@4.17.20", "b/package.json lodash@4.17.20"),
+			after:     inventory("a/package.json lodash@4.17.20", "b/package.json lodash@4.17.21"),
+			changes:   []string{"upgraded lodash b/package.json 4.17.20→4.17.21"},
+			unchanged: 1,
+		},
+		{
+			name:      "copies swap versions",
+			before:    inventory("a/package.json lodash@4.17.21", "b/package.json lodash@4.17.20"),
+			after:     inventory("a/package.json lodash@4.17.20", "b/package.json lodash@4.17.21"),
+			changes:   []string{"downgraded lodash a/package.json 4.17.21→4.17.20", "upgraded lodash b/package.json 4.17.20→4.17.21"},
+			unchanged: 0,
+		},
+		{
+			name:      "manifest moved",
+			before:    inventory("web/package.json react@18.2.0"),
+			after:     inventory("app/package.json react@18.2.0"),
+			unchanged: 1,
+		},
+		{
+			name:      "report against an SBOM",
+			before:    inventory("package.json react@18.2.0", "package.json lodash@4.17.20"),
+			after:     inventory("- react@18.2.0", "- lodash@4.17.21"),
+			changes:   []string{"upgraded lodash  4.17.20→4.17.21"},
+			unchanged: 1,
+		},
+		{
+			name:      "copy added and removed",
+			before:    inventory("a/package.json lodash@4.17.21", "old/package.json left-pad@1.3.0"),
+			after:     inventory("a/package.json lodash@4.17.21", "b/package.json lodash@4.17.21"),
+			changes:   []string{"removed left-pad old/package.json 1.3.0→", "added lodash b/package.json →4.17.21"},
+			unchanged: 1,
+		},
+		{
+			name:      "listed twice in one manifest",
+			before:    inventory("package.json lodash@4.17.21"),
+			after:     inventory("package.json lodash@4.17.21", "package.json lodash@4.17.21"),
+			unchanged: 1,
+		},
+		{
+			name:      "report against an SBOM without the v prefix",
+			before:    inventory("package.json react@v18.2.0", "package.json lodash@v4.17.20"),
+			after:     inventory("- react@18.2.0", "- lodash@4.17.21"),
+			changes:   []string{"upgraded lodash  v4.17.20→4.17.21"},
+			unchanged: 1,
+		},
+		{
+			name:      "same version written differently",
+			before:    inventory("a/package.json lodash@1.0", "b/package.json react@v18.2.0"),
+			after:     inventory("a/package.json lodash@1.0.0", "b/package.json react@18.2"),
+			unchanged: 2,
+		},
+		{
+			name:      "equal version pairs before a moved copy",
+			before:    inventory("a/package.json lodash@v4.17.21", "b/package.json lodash@4.17.20"),
+			after:     inventory("c/package.json lodash@4.17.21"),
+			changes:   []string{"removed lodash b/package.json 4.17.20→"},
+			unchanged: 1,
+		},
+		{
+			name:      "listed twice with and without the prefix",
+			before:    inventory("package.json lodash@4.17.21"),
+			after:     inventory("package.json lodash@v4.17.21", "package.json lodash@4.17.21"),
+			unchanged: 1,
+		},
+		{
+			name:    "versions that cannot be ordered",
+			before:  inventory("Dockerfile nginx@latest"),
+			after:   inventory("Dockerfile nginx@stable"),
+			changes: []string{"changed nginx Dockerfile latest→stable"},
+		},
+		{
+			name:       "newly vulnerable copy",
+			before:     inventory("a/package.json lodash@4.17.21"),
+			after:      inventory("a/package.json lodash@4.17.21", "b/package.json lodash@4.17.15!"),
+			changes:    []string{"added lodash b/package.json →4.17.15"},
+			unchanged:  1,
+			vulnerable: 1,
+		},
+		{
+			name:    "already vulnerable in another copy",
+			before:  inventory("a/package.json lodash@4.17.15!"),
+			after:   inventory("a/package.json lodash@4.17.21", "b/package.json lodash@4.17.15!"),
+			changes: []string{"upgraded lodash a/package.json 4.17.15→4.17.21", "added lodash b/package.json →4.17.15"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := Compare(test.before, test.after)
+			if got := describe(result); strings.Join(got, "; ") != strings.Join(test.changes, "; ") {
+				t.Errorf("changes %q, want %q", got, test.changes)
+			}
+			if result.Unchanged != test.unchanged {
+				t.Errorf("%d unchanged, want %d", result.Unchanged, test.unchanged)
+			}
+			if len(result.NewlyVulnerable) != test.vulnerable {
+				t.Errorf("%d newly vulnerable, want %d", len(result.NewlyVulnerable), test.vulnerable)
+			}
+		})
+	}
+}
+
+func TestFromPURL(t *testing.T) {
+	tests := []struct {
+		purl string
+		want Component
+	}{
+		{"pkg:npm/%40babel/core@7.24.0", Component{Ecosystem: "npm", Name: "@babel/core", Version: "7.24.0"}},
+		{"pkg:maven/org.slf4j/slf4j-api@2.0.13?type=jar", Component{Ecosystem: "maven", Name: "org.slf4j:slf4j-api", Version: "2.0.13"}},
+		{"pkg:golang/github.com/spf13/cobra@v1.8.0#cmd", Component{Ecosystem: "go", Name: "github.com/spf13/cobra", Version: "v1.8.0"}},
+		{"pkg:cargo/serde", Component{Ecosystem: "cargo", Name: "serde"}},
+		{"pkg:npm", Component{}},
+		{"npm/lodash@4.17.21", Component{}},
+	}
+	for _, test := range tests {
+		t.Run(test.purl, func(t *testing.T) {
+			got := fromPURL(test.purl)
+			if got.Ecosystem != test.want.Ecosystem || got.Name != test.want.Name || got.Version != test.want.Version {
+				t.Errorf("got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,618 @@
+// Package docker audits the base images of Dockerfiles and compose files against their registries.
+package docker
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"Sbom/audit"
+	"Sbom/version"
+)
+
+// --- Data Structures ---
+
+type ImageReference struct {
+	Registry   string // Registry host, e.g. registry-1.docker.io or ghcr.io
+	Repository string // e.g. library/alpine
+	Tag        string
+	Digest     string
+}
+
+type ImageInfo struct {
+	Image          ImageReference
+	Raw            string // Reference as written, after ARG/variable substitution
+	File           string
+	Line           int
+	Context        string // Dockerfile stage name or compose service
+	LatestTag      string
+	UpdateNeeded   bool
+	DigestPinned   bool
+	Bump           version.Bump
+	ReleasesBehind int    // Tags of the same shape between the current and the latest tag
+	Error          string // Why the image could not be checked
+	Status         string
+	PinningStatus  string
+}
+
+type TagList struct {
+	Name string   `json:"name"`
+	Tags []string `json:"tags"`
+}
+
+const dockerHubRegistry = "registry-1.docker.io"
+
+var fromPattern = regexp.MustCompile(`(?i)^\s*FROM\s+(?:--platform=\S+\s+)?(\S+)(?:\s+AS\s+(\S+))?`)
+var argPattern = regexp.MustCompile(`(?i)^\s*ARG\s+([A-Za-z_][A-Za-z0-9_]*)(?:=(.*))?$`)
+var variablePattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(?::?-([^}]*))?\}|\$([A-Za-z_][A-Za-z0-9_]*)`)
+var composeImagePattern = regexp.MustCompile(`^\s*image:\s*["']?([^"'\s#]+)["']?`)
+var composeServicePattern = regexp.MustCompile(`^  ([A-Za-z0-9._-]+):\s*$`)
+var numericTagPattern = regexp.MustCompile(`^v?(\d+(?:\.\d+)*)(.*)$`)
+
+// --- Dockerfile and Compose Parsing ---
+
+// substituteVariables: Expands $VAR, ${VAR} and ${VAR:-default} from known ARG values. The
+// environment of the audit is not consulted: it is not the environment of the build.
+func substituteVariables(value string, vars map[string]string) string {
+	return variablePattern.ReplaceAllStringFunc(value, func(ref string) string {
+		match := variablePattern.FindStringSubmatch(ref)
+		name, fallback := match[1], match[2]
+		if name == "" {
+			name = match[3]
+		}
+		if val, ok := vars[name]; ok && val != "" {
+			return val
+		}
+		return fallback
+	})
+}
+
+// parseDockerfile: Extracts FROM images, following global ARG defaults and skipping
+// references to earlier build stages. Only ARGs declared before the first FROM apply to
+// FROM lines; ARGs inside a stage are scoped to that stage's instructions.
+func parseDockerfile(filename string) ([]ImageInfo, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("error opening %s: %w", filename, err)
+	}
+	defer file.Close()
+
+	var images []ImageInfo
+	args := make(map[string]string)
+	stages := make(map[string]bool)
+	seenFrom := false
+
+	scanner := bufio.NewScanner(file)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Text()
+
+		if match := argPattern.FindStringSubmatch(line); match != nil {
+			if seenFrom {
+				continue
+			}
+			// An ARG without default keeps any earlier (global) value
+			if _, exists := args[match[1]]; !exists || match[2] != "" {
+				args[match[1]] = strings.Trim(strings.TrimSpace(match[2]), `"'`)
+			}
+			continue
+		}
+
+		match := fromPattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		seenFrom = true
+		raw := substituteVariables(match[1], args)
+		stage := match[2]
+		fromStage := stages[strings.ToLower(raw)]
+		if stage != "" {
+			stages[strings.ToLower(stage)] = true
+		}
+
+		// FROM builder / FROM scratch do not pull an image
+		if fromStage || strings.EqualFold(raw, "scratch") {
+			continue
+		}
+
+		images = append(images, ImageInfo{
+			Image:   parseImageReference(raw),
+			Raw:     raw,
+			File:    filename,
+			Line:    lineNumber,
+			Context: stage,
+		})
+	}
+	return images, scanner.Err()
+}
+
+// parseComposeFile: Extracts `image:` entries per service from a compose file
+func parseComposeFile(filename string) ([]ImageInfo, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("error opening %s: %w", filename, err)
+	}
+	defer file.Close()
+
+	var images []ImageInfo
+	service := ""
+	scanner := bufio.NewScanner(file)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Text()
+
+		if match := composeServicePattern.FindStringSubmatch(line); match != nil {
+			service = match[1]
+			continue
+		}
+		match := composeImagePattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		raw := substituteVariables(match[1], nil)
+		images = append(images, ImageInfo{
+			Image:   parseImageReference(raw),
+			Raw:     raw,
+			File:    filename,
+			Line:    lineNumber,
+			Context: service,
+		})
+	}
+	return images, scanner.Err()
+}
+
+// parseImageReference: Splits [registry/]repository[:tag][@digest], applying Docker Hub defaults
+func parseImageReference(raw string) ImageReference {
+	var ref ImageReference
+	name := raw
+	if before, digest, found := strings.Cut(name, "@"); found {
+		name, ref.Digest = before, digest
+	}
+	// A ':' after the last '/' is a tag, not a registry port
+	if idx := strings.LastIndex(name, ":"); idx > strings.LastIndex(name, "/") {
+		name, ref.Tag = name[:idx], name[idx+1:]
+	}
+	if ref.Tag == "" && ref.Digest == "" {
+		ref.Tag = "latest"
+	}
+
+	parts := strings.SplitN(name, "/", 2)
+	if len(parts) == 2 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		ref.Registry, ref.Repository = parts[0], parts[1]
+	} else {
+		ref.Registry, ref.Repository = dockerHubRegistry, name
+	}
+	if ref.Registry == dockerHubRegistry && !strings.Contains(ref.Repository, "/") {
+		ref.Repository = "library/" + ref.Repository
+	}
+	return ref
+}
+
+// --- OCI Distribution Client ---
+
+// registryBaseURL: REGISTRY_MIRROR_URL (e.g. http://localhost:5000) overrides every registry,
+// which lets audits run against a local or air-gapped registry.
+func registryBaseURL(registry string) string {
+	if mirror := os.Getenv("REGISTRY_MIRROR_URL"); mirror != "" {
+		return strings.TrimSuffix(mirror, "/")
+	}
+	if strings.HasPrefix(registry, "localhost") || strings.HasPrefix(registry, "127.0.0.1") {
+		return "http://" + registry
+	}
+	return "https://" + registry
+}
+
+// fetchBearerToken: Performs the anonymous token handshake described by a WWW-Authenticate header
+func fetchBearerToken(challenge string) (string, error) {
+	params := make(map[string]string)
+	challenge = strings.TrimPrefix(challenge, "Bearer ")
+	for _, part := range regexp.MustCompile(`(\w+)="([^"]*)"`).FindAllStringSubmatch(challenge, -1) {
+		params[part[1]] = part[2]
+	}
+	realm := params["realm"]
+	if realm == "" {
+		return "", fmt.Errorf("unsupported auth challenge: %s", challenge)
+	}
+
+	query := url.Values{}
+	if params["service"] != "" {
+		query.Set("service", params["service"])
+	}
+	if params["scope"] != "" {
+		query.Set("scope", params["scope"])
+	}
+	resp, err := http.Get(realm + "?" + query.Encode())
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token endpoint returned status %d", resp.StatusCode)
+	}
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", err
+	}
+	if token.Token != "" {
+		return token.Token, nil
+	}
+	return token.AccessToken, nil
+}
+
+// listTags: Calls /v2/<name>/tags/list, following Link pagination and bearer-token auth
+func listTags(ref ImageReference) ([]string, error) {
+	baseURL := registryBaseURL(ref.Registry)
+	next := fmt.Sprintf("%s/v2/%s/tags/list?n=1000", baseURL, ref.Repository)
+	token := ""
+
+	var tags []string
+	for next != "" {
+		req, err := http.NewRequest(http.MethodGet, next, nil)
+		if err != nil {
+			return nil, err
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode == http.StatusUnauthorized && token == "" {
+			challenge := resp.Header.Get("WWW-Authenticate")
+			resp.Body.Close()
+			token, err = fetchBearerToken(challenge)
+			if err != nil {
+				return nil, fmt.Errorf("registry authentication failed: %w", err)
+			}
+			continue
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("registry returned status %d for %s", resp.StatusCode, ref.Repository)
+		}
+
+		var list TagList
+		err = json.NewDecoder(resp.Body).Decode(&list)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("error decoding tag list: %w", err)
+		}
+		tags = append(tags, list.Tags...)
+
+		next = ""
+		if link := resp.Header.Get("Link"); link != "" {
+			// Link: </v2/<name>/tags/list?last=x&n=1000>; rel="next"
+			if start, end := strings.Index(link, "<"), strings.Index(link, ">"); start != -1 && end > start {
+				next = baseURL + link[start+1:end]
+			}
+		}
+	}
+	return tags, nil
+}
+
+// --- Tag Comparison ---
+
+// tagVersion: Splits a tag into numeric components and a variant suffix ("1.21-alpine3.18" -> [1 21], "-alpine3.18")
+func tagVersion(tag string) ([]int, string, bool) {
+	match := numericTagPattern.FindStringSubmatch(tag)
+	if match == nil {
+		return nil, "", false
+	}
+	var nums []int
+	for _, part := range strings.Split(match[1], ".") {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, "", false
+		}
+		nums = append(nums, n)
+	}
+	return nums, match[2], true
+}
+
+func compareTagVersions(a, b []int) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return len(a) - len(b)
+}
+
+// latestMatchingTag: Finds the highest tag with the same shape as the current one: the same
+// number of version components and the same variant suffix (e.g. -alpine, -bookworm-slim).
+func latestMatchingTag(current string, tags []string) string {
+	currentNums, currentSuffix, ok := tagVersion(current)
+	if !ok {
+		return ""
+	}
+	latest := current
+	latestNums := currentNums
+	for _, tag := range tags {
+		nums, suffix, ok := tagVersion(tag)
+		if !ok || suffix != currentSuffix || len(nums) != len(currentNums) {
+			continue
+		}
+		if compareTagVersions(nums, latestNums) > 0 {
+			latest, latestNums = tag, nums
+		}
+	}
+	return latest
+}
+
+// classifyTagUpdate: Classifies the step between two tags of the same shape by their version components,
+// and counts the tags of that shape in between
+func classifyTagUpdate(image, current, latest string, tags []string) (version.Bump, int) {
+	currentNums, currentSuffix, _ := tagVersion(current)
+	latestNums, _, _ := tagVersion(latest)
+	bump := version.For("docker", image).Classify(joinTagVersion(currentNums), joinTagVersion(latestNums))
+
+	behind := 0
+	for _, tag := range tags {
+		nums, suffix, ok := tagVersion(tag)
+		if !ok || suffix != currentSuffix || len(nums) != len(currentNums) {
+			continue
+		}
+		if compareTagVersions(nums, currentNums) > 0 && compareTagVersions(nums, latestNums) <= 0 {
+			behind++
+		}
+	}
+	return bump, behind
+}
+
+func joinTagVersion(nums []int) string {
+	parts := make([]string, len(nums))
+	for i, n := range nums {
+		parts[i] = strconv.Itoa(n)
+	}
+	return strings.Join(parts, ".")
+}
+
+// --- Core Check Logic ---
+
+func checkImageUpdate(info ImageInfo, cache map[string][]string) ImageInfo {
+	info.DigestPinned = info.Image.Digest != ""
+	if info.DigestPinned {
+		info.PinningStatus = "🔒 Digest pinned"
+	} else {
+		info.PinningStatus = "⚠️ Tag only (mutable)"
+	}
+
+	if strings.Contains(info.Raw, "$") {
+		info.Error = "unresolved variable in image reference"
+		info.Status = "❌ Unresolved variable in image reference"
+		return info
+	}
+	if info.Image.Tag == "" {
+		info.Status = "🔒 Digest only (version unknown)"
+		return info
+	}
+	if _, _, ok := tagVersion(info.Image.Tag); !ok {
+		info.Status = fmt.Sprintf("⚠️ Unversioned tag (`%s`)", info.Image.Tag)
+		return info
+	}
+
+	key := info.Image.Registry + "/" + info.Image.Repository
+	tags, ok := cache[key]
+	if !ok {
+		var err error
+		tags, err = listTags(info.Image)
+		if err != nil {
+			info.Error = err.Error()
+			info.Status = "❌ Error: " + info.Error
+			return info
+		}
+		cache[key] = tags
+	}
+
+	info.LatestTag = latestMatchingTag(info.Image.Tag, tags)
+	if info.LatestTag != "" && info.LatestTag != info.Image.Tag {
+		info.UpdateNeeded = true
+		info.Bump, info.ReleasesBehind = classifyTagUpdate(info.Image.Repository, info.Image.Tag, info.LatestTag, tags)
+		info.Status = audit.UpdateStatus(info.Bump)
+	} else {
+		info.LatestTag = info.Image.Tag
+		info.Status = "✅ Up to Date"
+	}
+	return info
+}
+
+// --- Output Function (Markdown Table) ---
+
+// reportTemplate is the default image report
+var reportTemplate = audit.Template{
+	Name: "docker",
+	Text: `## 🐳 Container Base Image Audit
+
+This report compares the image tags in your Dockerfiles and compose files against their registries.
+
+{{summary .Report}}{{table .Results .Report.Findings}}`,
+	Funcs: map[string]interface{}{
+		"table": func(results []ImageInfo, findings []audit.Finding) string {
+			return audit.Capture(func(writer *bufio.Writer) { writeTable(writer, results, findings) })
+		},
+	},
+}
+
+// printReport: Renders the results ([]ImageInfo) through reportTemplate
+func printReport(results []ImageInfo, report *audit.Report, filename string) error {
+	return reportTemplate.Write(filename, audit.TemplateData{Report: report, Results: results})
+}
+
+// writeTable writes the image table (shared with combined scan reports) from the report's findings;
+// results, in the same order, add the registries, stages and pinning
+func writeTable(writer *bufio.Writer, results []ImageInfo, findings []audit.Finding) {
+	writer.WriteString("| # | Image | Stage / Service | Location | Pinning | Status | Change | Current Tag | Latest Tag |\n")
+	writer.WriteString("| :---: | :--- | :--- | :--- | :---: | :---: | :---: | :---: | :---: |\n")
+
+	for i, finding := range findings {
+		info := results[i]
+		statusDisplay := finding.Status
+		if finding.UpdateNeeded {
+			statusDisplay = "**" + statusDisplay + "**"
+		}
+		statusDisplay = audit.AcceptedStatus(statusDisplay, finding)
+
+		image := finding.Name
+		if info.Image.Registry != dockerHubRegistry {
+			image = info.Image.Registry + "/" + image
+		}
+
+		line := fmt.Sprintf("| %d | `%s` | %s | `%s:%d` | %s | %s | %s | `%s` | `%s` |\n",
+			i+1, strings.TrimPrefix(image, "library/"), info.Context, info.File, finding.Line,
+			info.PinningStatus, statusDisplay, audit.ChangeLabel(finding.Bump, finding.ReleasesBehind), finding.CurrentVersion, finding.LatestVersion)
+		writer.WriteString(line)
+	}
+}
+
+// isComposeFile reports whether a file name is a compose file rather than a Dockerfile
+func isComposeFile(filename string) bool {
+	base := strings.ToLower(filepath.Base(filename))
+	return strings.HasSuffix(base, ".yml") || strings.HasSuffix(base, ".yaml")
+}
+
+// checkImages runs the registry checks, fetching each repository's tag list only once
+func checkImages(images []ImageInfo) []ImageInfo {
+	sort.SliceStable(images, func(i, j int) bool { return images[i].File < images[j].File })
+
+	fmt.Printf("Starting audit of %d container images...\n", len(images))
+
+	cache := make(map[string][]string)
+	var results []ImageInfo
+	for _, image := range images {
+		fmt.Printf("-> Checking %s (%s:%d)\n", image.Raw, image.File, image.Line)
+		results = append(results, checkImageUpdate(image, cache))
+	}
+	return results
+}
+
+// ScanManifest audits one Dockerfile or compose file for combined scan reports; its table is
+// written once the findings are added to the report
+func ScanManifest(filename string) ([]audit.Finding, audit.TableWriter, error) {
+	var images []ImageInfo
+	var err error
+	if isComposeFile(filename) {
+		images, err = parseComposeFile(filename)
+	} else {
+		images, err = parseDockerfile(filename)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	results := checkImages(images)
+	table := func(writer *bufio.Writer, findings []audit.Finding) { writeTable(writer, results, findings) }
+	return toFindings(results), table, nil
+}
+
+// toFindings converts the audited images into the ecosystem-neutral model
+func toFindings(results []ImageInfo) []audit.Finding {
+	var findings []audit.Finding
+	for _, info := range results {
+		findings = append(findings, audit.Finding{
+			Name:           info.Image.Repository,
+			CurrentVersion: info.Image.Tag,
+			LatestVersion:  info.LatestTag,
+			Status:         info.Status,
+			Compared:       info.LatestTag != "",
+			Error:          info.Error,
+			UpdateNeeded:   info.UpdateNeeded,
+			Bump:           info.Bump,
+			ReleasesBehind: info.ReleasesBehind,
+			Line:           info.Line,
+		})
+	}
+	return findings
+}
+
+// Run audits docker/Dockerfile and docker/docker-compose.yml (or the files given as arguments)
+func Run(args []string) {
+	dockerfiles := []string{"docker/Dockerfile"}
+	composeFiles := []string{"docker/docker-compose.yml"}
+	if len(args) > 0 {
+		dockerfiles, composeFiles = nil, nil
+		for _, arg := range args {
+			if isComposeFile(arg) {
+				composeFiles = append(composeFiles, arg)
+			} else {
+				dockerfiles = append(dockerfiles, arg)
+			}
+		}
+	}
+	const outputFilePath = "docker/report.md"
+
+	// 1. Extract images from Dockerfiles and compose files
+	var images []ImageInfo
+	for _, filename := range dockerfiles {
+		found, err := parseDockerfile(filename)
+		if err != nil {
+			fmt.Printf("⚠️ Warning: %v\n", err)
+			continue
+		}
+		images = append(images, found...)
+	}
+	for _, filename := range composeFiles {
+		found, err := parseComposeFile(filename)
+		if err != nil {
+			fmt.Printf("⚠️ Warning: %v\n", err)
+			continue
+		}
+		images = append(images, found...)
+	}
+
+	if len(images) == 0 {
+		fmt.Println("No container images found to audit.")
+		return
+	}
+
+	// 2. Perform the checks, one file at a time so the JSON report can attribute them
+	files := append(append([]string{}, dockerfiles...), composeFiles...)
+	report := audit.NewReport("docker", strings.Join(files, " "))
+	var results []ImageInfo
+	for _, filename := range files {
+		started := time.Now()
+		var fileImages []ImageInfo
+		for _, image := range images {
+			if image.File == filename {
+				fileImages = append(fileImages, image)
+			}
+		}
+		if len(fileImages) == 0 {
+			continue
+		}
+		checked := checkImages(fileImages)
+		report.Add(audit.NewSource(filename, "docker", started, toFindings(checked), nil))
+		results = append(results, checked...)
+	}
+	report.Finish()
+
+	// 3. Write the final Markdown and JSON reports
+	err := printReport(results, report, outputFilePath)
+	if err == nil {
+		err = report.WriteFiles(outputFilePath)
+	}
+	if err != nil {
+		fmt.Printf("Fatal Error writing report: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("✅ Operation completed successfully. Results saved in %s.\n", audit.OutputFiles(reportTemplate.Name, outputFilePath))
+}
@@ -0,0 +1,76 @@
+package docker
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseDockerfileArgs(t *testing.T) {
+	tests := []struct {
+		name       string
+		dockerfile string
+		want       []string
+	}{
+		{
+			name:       "global ARG default",
+			dockerfile: "ARG VERSION=3.19\nFROM alpine:${VERSION}\n",
+			want:       []string{"alpine:3.19"},
+		},
+		{
+			name:       "$VAR form and quoted default",
+			dockerfile: "ARG IMAGE=\"node\"\nARG TAG='20-slim'\nFROM $IMAGE:$TAG\n",
+			want:       []string{"node:20-slim"},
+		},
+		{
+			name:       "inline fallback",
+			dockerfile: "ARG TAG\nFROM golang:${TAG:-1.22}\n",
+			want:       []string{"golang:1.22"},
+		},
+		{
+			name:       "ARG without default keeps the earlier value",
+			dockerfile: "ARG TAG=1.21\nARG TAG\nFROM golang:${TAG}\n",
+			want:       []string{"golang:1.21"},
+		},
+		{
+			name:       "stage ARG does not apply to later FROM lines",
+			dockerfile: "ARG TAG=3.18\nFROM alpine:${TAG} AS build\nARG TAG=3.20\nFROM alpine:${TAG}\n",
+			want:       []string{"alpine:3.18", "alpine:3.18"},
+		},
+		{
+			name:       "ARG declared only inside a stage",
+			dockerfile: "FROM debian:12 AS build\nARG RUNTIME=distroless/static\nFROM ${RUNTIME:-gcr.io/distroless/base}\n",
+			want:       []string{"debian:12", "gcr.io/distroless/base"},
+		},
+		{
+			name:       "stage references and scratch are skipped",
+			dockerfile: "FROM golang:1.22 AS Build\nFROM build\nFROM scratch\n",
+			want:       []string{"golang:1.22"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv("TAG", "from-the-environment")
+			filename := filepath.Join(t.TempDir(), "Dockerfile")
+			if err := os.WriteFile(filename, []byte(test.dockerfile), 0644); err != nil {
+				t.Fatal(err)
+			}
+			images, err := parseDockerfile(filename)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, image := range images {
+				got = append(got, image.Raw)
+			}
+			if len(got) != len(test.want) {
+				t.Fatalf("got %v, want %v", got, test.want)
+			}
+			for i := range got {
+				if got[i] != test.want[i] {
+					t.Errorf("image %d: got %q, want %q", i, got[i], test.want[i])
+				}
+			}
+		})
+	}
+}
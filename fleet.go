@@ -0,0 +1,336 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"Sbom/audit"
+	"Sbom/forge"
+
+	"github.com/google/go-github/v62/github"
+)
+
+// RepoScan holds the outcome of scanning one repository through the GitHub API
+type RepoScan struct {
+	FullName  string
+	Branch    string
+	Manifests int
+	Error     string
+	Details   string // Markdown tables written by the ecosystem auditors
+}
+
+// --- Repository Discovery ---
+
+// listOrgRepos lists the active repositories of an organisation; archived repositories and forks are skipped
+func listOrgRepos(client *github.Client, org string) ([]string, error) {
+	opts := &github.RepositoryListByOrgOptions{
+		Type:        "all",
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+
+	var names []string
+	for {
+		repos, resp, err := client.Repositories.ListByOrg(context.Background(), org, opts)
+		if err != nil {
+			return nil, fmt.Errorf("error listing repositories of %s: %w", org, err)
+		}
+		for _, repo := range repos {
+			if repo.GetArchived() || repo.GetFork() {
+				continue
+			}
+			names = append(names, repo.GetFullName())
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return names, nil
+}
+
+// readRepoList reads owner/repo names from a file; anything after the name (e.g. a version) is ignored
+func readRepoList(filename string) ([]string, error) {
+	lines, err := readRepos(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, line := range lines {
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		name := strings.Fields(line)[0]
+		if strings.Count(name, "/") != 1 {
+			fmt.Printf("⚠️ Format Error: Line '%s' skipped.\n", line)
+			continue
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// --- Manifest Download ---
+
+// fetchRepoManifests copies every supported manifest of the default branch into dir, using the
+// recursive trees API so nothing is cloned. Returns the branch that was read.
+func fetchRepoManifests(client *github.Client, owner, repo, dir string) (string, error) {
+	ctx := context.Background()
+	repository, _, err := client.Repositories.Get(ctx, owner, repo)
+	if err != nil {
+		return "", fmt.Errorf("error fetching repository: %w", err)
+	}
+	branch := repository.GetDefaultBranch()
+
+	tree, _, err := client.Git.GetTree(ctx, owner, repo, branch, true)
+	if err != nil {
+		return branch, fmt.Errorf("error fetching tree of %s: %w", branch, err)
+	}
+	if tree.GetTruncated() {
+		fmt.Printf("⚠️ Warning: The tree of %s/%s is too large and was truncated; some manifests may be missing.\n", owner, repo)
+	}
+
+	for _, entry := range tree.Entries {
+		if entry.GetType() != "blob" || !isFleetManifest(entry.GetPath()) {
+			continue
+		}
+
+		content, _, err := client.Git.GetBlobRaw(ctx, owner, repo, entry.GetSHA())
+		if err != nil {
+			fmt.Printf("⚠️ Warning: Could not download %s: %v\n", entry.GetPath(), err)
+			continue
+		}
+		target := filepath.Join(dir, filepath.FromSlash(entry.GetPath()))
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return branch, err
+		}
+		if err := os.WriteFile(target, content, 0o644); err != nil {
+			return branch, err
+		}
+	}
+	return branch, nil
+}
+
+// isFleetManifest reports whether a tree path is needed by an auditor (manifests, plus lockfiles they read)
+func isFleetManifest(treePath string) bool {
+	for _, segment := range strings.Split(path.Dir(treePath), "/") {
+		if skippedDirs[segment] {
+			return false
+		}
+	}
+	base := path.Base(treePath)
+	return detectEcosystem(treePath) != "" || base == "Cargo.lock" || base == ".gitignore"
+}
+
+// --- Fleet Scan ---
+
+// scanRepository downloads the manifests of one repository, runs the ecosystem audits on them and
+// adds one source per manifest to the report
+func scanRepository(client *github.Client, router *forge.Router, report *audit.Report, fullName string) RepoScan {
+	result := RepoScan{FullName: fullName}
+	owner, repo, _ := strings.Cut(fullName, "/")
+
+	dir, err := os.MkdirTemp("", "sbom-fleet-")
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	defer os.RemoveAll(dir)
+
+	result.Branch, err = fetchRepoManifests(client, owner, repo, dir)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	// The auditors read the download directory; the report names files relative to the repository
+	manifests, err := findManifests(dir)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Manifests = len(manifests)
+	repoPath := func(p string) string {
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return filepath.ToSlash(p)
+		}
+		return filepath.ToSlash(rel)
+	}
+
+	var details bytes.Buffer
+	writer := bufio.NewWriter(&details)
+	for _, manifest := range manifests {
+		relPath := repoPath(manifest.Path)
+		fmt.Printf("📁 Scanning %s: %s (%s)...\n", fullName, relPath, manifest.Ecosystem)
+		_, _ = writer.WriteString(fmt.Sprintf("#### 📁 `%s` (%s)\n\n", relPath, manifest.Ecosystem))
+		started := time.Now()
+		findings, table, err := scanManifest(client, router, manifest)
+		if err != nil {
+			fmt.Printf("⚠️ Warning: %v\n", err)
+			_, _ = writer.WriteString(fmt.Sprintf("> ❌ Error: %v\n", err))
+		}
+		// Lines are looked up in the download, reported per repository
+		source := audit.NewSource(manifest.Path, manifest.Ecosystem, started, findings, err)
+		source.Path = fullName + "/" + relPath
+		source.Repository = fullName
+		for i := range source.Findings {
+			if source.Findings[i].Manifest != "" {
+				source.Findings[i].Manifest = fullName + "/" + repoPath(source.Findings[i].Manifest)
+			}
+		}
+		report.Add(source)
+		if table != nil {
+			table(writer, report.Sources[len(report.Sources)-1].Findings)
+		}
+		_, _ = writer.WriteString("\n")
+	}
+	_ = writer.Flush()
+	// The tables name files as the auditors were given them, inside the download directory
+	result.Details = strings.ReplaceAll(details.String(), dir+string(filepath.Separator), "")
+	return result
+}
+
+// fleetTemplate orders the fleet report by urgency: risks, per-repository summary, then the full tables
+var fleetTemplate = audit.Template{
+	Name: "fleet",
+	Text: `# 🛰️ Fleet Dependency Report
+
+This report aggregates the dependency audits of **{{len .Results.Scans}}** repositories ({{code .Results.Target}}), read through the GitHub API.
+
+{{summary .Report}}## 🚨 Vulnerable or Archived Dependencies
+
+{{riskTable .Report}}
+---
+
+## 📊 Repository Summary
+
+{{repositoryTable .Results.Scans .Report}}
+---
+
+## 📦 Repository Details
+
+{{range .Results.Scans}}{{if .Details}}### 📦 {{code .FullName}}
+
+{{.Details}}---
+
+{{end}}{{end}}`,
+	Funcs: map[string]interface{}{
+		"riskTable": func(report *audit.Report) string {
+			return audit.Capture(func(writer *bufio.Writer) { writeRiskTable(writer, report) })
+		},
+		"repositoryTable": func(scans []RepoScan, report *audit.Report) string {
+			return audit.Capture(func(writer *bufio.Writer) { writeRepositoryTable(writer, scans, report) })
+		},
+	},
+}
+
+// FleetReport is the model of the fleet report: the organisation or repository list, and every repository scanned
+type FleetReport struct {
+	Target string
+	Scans  []RepoScan
+}
+
+// writeFleetOutput renders the aggregated fleet report through fleetTemplate
+func writeFleetOutput(target string, scans []RepoScan, report *audit.Report, filename string) error {
+	data := audit.TemplateData{Report: report, Results: FleetReport{Target: target, Scans: scans}}
+	return fleetTemplate.Write(filename, data)
+}
+
+// writeRiskTable lists every vulnerable or archived dependency of the report, grouped by repository;
+// issues the baseline accepts are left out
+func writeRiskTable(writer *bufio.Writer, report *audit.Report) {
+	_, _ = writer.WriteString("| # | Repository | Manifest | Dependency | Current Version | Latest Version | Issue |\n")
+	_, _ = writer.WriteString("| :---: | :--- | :--- | :--- | :---: | :---: | :--- |\n")
+	index := 0
+	for _, source := range report.Sources {
+		manifest := strings.TrimPrefix(source.Path, source.Repository+"/")
+		for _, finding := range source.Findings {
+			var issues []string
+			if finding.Vulnerable && !finding.IsAccepted(audit.Vulnerable) {
+				issues = append(issues, "🚨 Security fix available / yanked")
+			}
+			if finding.Archived && !finding.IsAccepted(audit.Archived) {
+				issues = append(issues, "⛔️ Archived upstream")
+			}
+			if len(issues) == 0 {
+				continue
+			}
+			index++
+			_, _ = writer.WriteString(fmt.Sprintf("| %d | [`%s`](https://github.com/%s) | `%s` | `%s` | `%s` | `%s` | %s |\n",
+				index, source.Repository, source.Repository, manifest, finding.Name,
+				finding.CurrentVersion, finding.LatestVersion, strings.Join(issues, ", ")))
+		}
+	}
+	if index == 0 {
+		_, _ = writer.WriteString("| - | ✅ No vulnerable or archived dependencies found | | | | | |\n")
+	}
+}
+
+// writeRepositoryTable writes one summary line per repository, counting the report's findings
+// of the repository after the baseline
+func writeRepositoryTable(writer *bufio.Writer, scans []RepoScan, report *audit.Report) {
+	_, _ = writer.WriteString("| # | Repository | Branch | Manifests | Dependencies | Updates | Major Updates | Vulnerable | Archived |\n")
+	_, _ = writer.WriteString("| :---: | :--- | :---: | :---: | :---: | :---: | :---: | :---: | :---: |\n")
+	for i, scan := range scans {
+		if scan.Error != "" {
+			_, _ = writer.WriteString(fmt.Sprintf("| %d | `%s` | `%s` | ❌ Error: %s | | | | | |\n", i+1, scan.FullName, scan.Branch, scan.Error))
+			continue
+		}
+		var findings []audit.Finding
+		for _, source := range report.Sources {
+			if source.Repository == scan.FullName {
+				findings = append(findings, source.Findings...)
+			}
+		}
+		summary := audit.Summarize(findings)
+		_, _ = writer.WriteString(fmt.Sprintf("| %d | `%s` | `%s` | %d | %d | %d | %d | %d | %d |\n",
+			i+1, scan.FullName, scan.Branch, scan.Manifests, summary.Dependencies, summary.Outdated, summary.Major, summary.Vulnerable, summary.Archived))
+	}
+}
+
+// runFleetScan audits every repository of an organisation, or those listed in a file, and writes the fleet report
+func runFleetScan(target, outputFile string) error {
+	client := createGitHubClient()
+	router := forge.NewRouter(client)
+
+	var names []string
+	var err error
+	if _, statErr := os.Stat(target); statErr == nil {
+		names, err = readRepoList(target)
+	} else {
+		names, err = listOrgRepos(client, target)
+	}
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		fmt.Printf("No repositories found for %s.\n", target)
+		return nil
+	}
+
+	fmt.Printf("Starting fleet scan of %d repositories...\n", len(names))
+	report := audit.NewReport("fleet", target)
+	var scans []RepoScan
+	for _, name := range names {
+		fmt.Printf("-> Scanning repository %s...\n", name)
+		scan := scanRepository(client, router, report, name)
+		if scan.Error != "" {
+			report.Errors = append(report.Errors, fmt.Sprintf("%s: %s", name, scan.Error))
+		}
+		scans = append(scans, scan)
+	}
+	report.Finish()
+
+	if err := writeFleetOutput(target, scans, report, outputFile); err != nil {
+		return err
+	}
+	return report.WriteFiles(outputFile)
+}
@@ -0,0 +1,150 @@
+package forge
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const bitbucketAPIURL = "https://api.bitbucket.org/2.0"
+
+// Bitbucket fulfils Forge through the Bitbucket Cloud REST API (2.0). Bitbucket has neither
+// releases nor archived repositories, so annotated tag messages stand in for release notes.
+type Bitbucket struct {
+	apiURL string
+	token  string // Access token, or "username:app_password" for basic authentication
+}
+
+type bitbucketTagPage struct {
+	Values []struct {
+		Name    string `json:"name"`
+		Message string `json:"message"`
+		Target  struct {
+			Date time.Time `json:"date"`
+		} `json:"target"`
+	} `json:"values"`
+	Next string `json:"next"` // URL of the next page, empty on the last one
+}
+
+func NewBitbucket(token string) *Bitbucket {
+	return &Bitbucket{apiURL: bitbucketAPIURL, token: token}
+}
+
+func (b *Bitbucket) Name() string {
+	return "Bitbucket"
+}
+
+func (b *Bitbucket) headers() map[string]string {
+	headers := map[string]string{}
+	switch {
+	case strings.Contains(b.token, ":"):
+		headers["Authorization"] = "Basic " + base64.StdEncoding.EncodeToString([]byte(b.token))
+	case b.token != "":
+		headers["Authorization"] = "Bearer " + b.token
+	}
+	return headers
+}
+
+// get: Performs an authenticated GET and decodes the JSON response into target
+func (b *Bitbucket) get(apiURL string, target interface{}) error {
+	return getJSON(b.Name(), apiURL, b.headers(), target)
+}
+
+func (b *Bitbucket) repositoryURL(repo Repository) string {
+	return fmt.Sprintf("%s/repositories/%s/%s", b.apiURL, url.PathEscape(repo.Owner), url.PathEscape(repo.Name))
+}
+
+// tags: Lists the most recently created tags, newest first
+func (b *Bitbucket) tags(repo Repository) (bitbucketTagPage, error) {
+	var page bitbucketTagPage
+	err := b.get(b.repositoryURL(repo)+"/refs/tags?sort=-target.date&pagelen=30", &page)
+	return page, err
+}
+
+// IsArchived: Bitbucket Cloud cannot archive repositories; the lookup only checks the repository exists
+func (b *Bitbucket) IsArchived(repo Repository) (bool, error) {
+	var details struct {
+		FullName string `json:"full_name"`
+	}
+	return false, b.get(b.repositoryURL(repo), &details)
+}
+
+// LatestRelease: Without releases, callers fall back to ListTags and pick the highest version
+func (b *Bitbucket) LatestRelease(repo Repository) (Release, error) {
+	return Release{}, fmt.Errorf("Bitbucket has no releases")
+}
+
+func (b *Bitbucket) ListReleases(repo Repository) ([]Release, error) {
+	page, err := b.tags(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	var releases []Release
+	for _, tag := range page.Values {
+		releases = append(releases, Release{TagName: tag.Name, Name: tag.Name, Body: tag.Message, PublishedAt: tag.Target.Date})
+	}
+	return releases, nil
+}
+
+// ReleasesUntil: Follows the tag pages' next links
+func (b *Bitbucket) ReleasesUntil(repo Repository, reached func(Release) bool) ([]Release, error) {
+	var result []Release
+	apiURL := b.repositoryURL(repo) + "/refs/tags?sort=-target.date&pagelen=100"
+	for page := 0; page < maxReleasePages && apiURL != ""; page++ {
+		var tags bitbucketTagPage
+		if err := b.get(apiURL, &tags); err != nil {
+			return result, err
+		}
+		var batch []Release
+		for _, tag := range tags.Values {
+			batch = append(batch, Release{TagName: tag.Name, Name: tag.Name, Body: tag.Message, PublishedAt: tag.Target.Date})
+		}
+		result = append(result, batch...)
+		if allReached(batch, reached) {
+			break
+		}
+		apiURL = tags.Next
+	}
+	return result, nil
+}
+
+func (b *Bitbucket) ListTags(repo Repository) ([]string, error) {
+	page, err := b.tags(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, tag := range page.Values {
+		names = append(names, tag.Name)
+	}
+	return names, nil
+}
+
+// ReadFile: Reads the file from the repository's main branch
+func (b *Bitbucket) ReadFile(repo Repository, path string) ([]byte, error) {
+	var details struct {
+		MainBranch struct {
+			Name string `json:"name"`
+		} `json:"mainbranch"`
+	}
+	if err := b.get(b.repositoryURL(repo), &details); err != nil {
+		return nil, err
+	}
+	return getRaw(b.Name(), b.repositoryURL(repo)+"/src/"+url.PathEscape(details.MainBranch.Name)+"/"+path, b.headers())
+}
+
+func (b *Bitbucket) WebURL(repo Repository) string {
+	return fmt.Sprintf("https://bitbucket.org/%s", repo.Path())
+}
+
+func (b *Bitbucket) ReleaseURL(repo Repository, tag string) string {
+	return fmt.Sprintf("%s/src/%s", b.WebURL(repo), url.PathEscape(tag))
+}
+
+func (b *Bitbucket) TagsURL(repo Repository) string {
+	return b.WebURL(repo) + "/downloads/?tab=tags"
+}
@@ -0,0 +1,435 @@
+// Package forge routes release, tag and repository lookups to the code forge
+// (GitHub, GitLab, Gitea/Forgejo, Bitbucket) that hosts a dependency's source repository,
+// falling back to plain git (smart HTTP or a local repository) where no API is available.
+package forge
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v62/github"
+)
+
+// --- Data Structures ---
+
+// Repository identifies a project on a forge. Owner may contain '/' for GitLab subgroups;
+// for Bitbucket it is the workspace.
+type Repository struct {
+	Host  string
+	Owner string
+	Name  string
+}
+
+// Path returns "owner/name", the form used in reports and forge API paths
+func (r Repository) Path() string {
+	if r.Owner == "" {
+		return r.Name // Local repositories have no owner
+	}
+	return r.Owner + "/" + r.Name
+}
+
+// Release is a published release of a repository
+type Release struct {
+	TagName     string
+	Name        string
+	Body        string
+	PublishedAt time.Time // Zero when the forge does not tell
+}
+
+// RateLimitError is returned when a forge refuses further requests until Reset
+type RateLimitError struct {
+	Forge string
+	Reset time.Time
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("%s rate limit exceeded until %s", e.Forge, e.Reset.Format(time.RFC1123))
+}
+
+// Forge is implemented by every supported code forge
+type Forge interface {
+	// Name is the display name used in reports ("GitHub", "GitLab", ...)
+	Name() string
+	IsArchived(repo Repository) (bool, error)
+	// LatestRelease returns the newest published (non-draft, non-prerelease) release
+	LatestRelease(repo Repository) (Release, error)
+	// ListReleases returns the most recent releases, newest first
+	ListReleases(repo Repository) ([]Release, error)
+	// ReleasesUntil pages through the releases, newest first, until reached returns true for every
+	// release of a page (e.g. all are older by version than the one in use) or the history ends, up
+	// to maxReleasePages pages. One reached release is not enough: a backport published after a
+	// newer release would end the paging before that release.
+	ReleasesUntil(repo Repository, reached func(Release) bool) ([]Release, error)
+	// ReadFile returns a file from the default branch, e.g. CHANGELOG.md
+	ReadFile(repo Repository, path string) ([]byte, error)
+	// ListTags returns the most recent tag names
+	ListTags(repo Repository) ([]string, error)
+	// WebURL links to the repository's home page
+	WebURL(repo Repository) string
+	// ReleaseURL links to the release page of a tag
+	ReleaseURL(repo Repository, tag string) string
+	// TagsURL links to the repository's tag list
+	TagsURL(repo Repository) string
+}
+
+// Prefetcher is implemented by forges that can load many repositories' metadata in bulk
+type Prefetcher interface {
+	Prefetch(repos []Repository) error
+}
+
+// Commit is a commit a branch, tag or SHA resolves to
+type Commit struct {
+	SHA  string
+	Date time.Time // Committer date
+}
+
+// Comparison relates two refs: head is AheadBy commits ahead of base and BehindBy commits behind it
+type Comparison struct {
+	AheadBy  int
+	BehindBy int
+}
+
+// CommitHistory is implemented by forges that can resolve and compare refs, which is what
+// branch- and commit-pinned dependencies are measured with
+type CommitHistory interface {
+	DefaultBranch(repo Repository) (string, error)
+	// Commit resolves a branch, tag or SHA
+	Commit(repo Repository, ref string) (Commit, error)
+	Compare(repo Repository, base, head string) (Comparison, error)
+}
+
+const githubHost = "github.com"
+
+// maxReleasePages bounds ReleasesUntil for repositories with a very long release history
+const maxReleasePages = 10
+
+// allReached reports whether reached holds for every release of a page
+func allReached(releases []Release, reached func(Release) bool) bool {
+	for _, release := range releases {
+		if !reached(release) {
+			return false
+		}
+	}
+	return len(releases) > 0
+}
+
+// reachedBack reports whether releases, newest first, reach back far enough: one is reached and
+// none listed after it is not
+func reachedBack(releases []Release, reached func(Release) bool) bool {
+	found := false
+	for _, release := range releases {
+		switch {
+		case reached(release):
+			found = true
+		case found:
+			return false
+		}
+	}
+	return found
+}
+
+// --- Repository URL Parsing ---
+
+// ParseRepoURL: Extracts the host and project path from the repository URL formats found in
+// package manifests: https/git/ssh URLs, scp-style git@host:owner/repo, npm shorthands
+// (github:owner/repo, gitlab:owner/repo) and bare owner/repo (GitHub). It only reads the string:
+// file:// URLs and absolute or ./ paths are rejected, and Router.Resolve looks for local repositories.
+func ParseRepoURL(raw string) (Repository, bool) {
+	raw = strings.TrimSpace(raw)
+	raw = strings.TrimPrefix(raw, "git+")
+	raw = strings.Split(raw, "#")[0]
+	if strings.HasPrefix(raw, "file://") || strings.HasPrefix(raw, "/") || strings.HasPrefix(raw, ".") {
+		return Repository{}, false
+	}
+
+	host := ""
+	rest := raw
+	switch {
+	case strings.Contains(raw, "://"):
+		parsed, err := url.Parse(raw)
+		if err != nil {
+			return Repository{}, false
+		}
+		host, rest = parsed.Hostname(), parsed.Path
+	case strings.HasPrefix(raw, "github:"):
+		host, rest = githubHost, strings.TrimPrefix(raw, "github:")
+	case strings.HasPrefix(raw, "gitlab:"):
+		host, rest = "gitlab.com", strings.TrimPrefix(raw, "gitlab:")
+	case strings.HasPrefix(raw, "bitbucket:"):
+		host, rest = "bitbucket.org", strings.TrimPrefix(raw, "bitbucket:")
+	case strings.Contains(raw, "@") && strings.Contains(raw, ":"):
+		// scp-like syntax: git@gitlab.example.com:group/project.git
+		userHost, path, _ := strings.Cut(raw, ":")
+		host, rest = userHost[strings.LastIndex(userHost, "@")+1:], path
+	default:
+		host = githubHost
+	}
+
+	rest = strings.TrimSuffix(strings.Trim(rest, "/"), ".git")
+	// GitLab web URLs separate the project path from pages with "/-/"
+	rest = strings.Split(rest, "/-/")[0]
+
+	var parts []string
+	for _, part := range strings.Split(rest, "/") {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	if len(parts) < 2 {
+		return Repository{}, false
+	}
+
+	host = strings.ToLower(host)
+	if host == "www.github.com" {
+		host = githubHost
+	}
+	if host == githubHost {
+		// GitHub has no nested owners; drop /tree/main/... style suffixes
+		parts = parts[:2]
+	}
+	return Repository{
+		Host:  host,
+		Owner: strings.Join(parts[:len(parts)-1], "/"),
+		Name:  parts[len(parts)-1],
+	}, true
+}
+
+// --- HTTP Helper ---
+
+// getJSON: Performs a GET against a forge's REST API and decodes the JSON response into target.
+// HTTP 429 responses become a RateLimitError, using the reset time the forge advertises.
+func getJSON(forgeName, apiURL string, headers map[string]string, target interface{}) error {
+	jsonHeaders := map[string]string{"Accept": "application/json"}
+	for key, value := range headers {
+		jsonHeaders[key] = value
+	}
+	data, err := getRaw(forgeName, apiURL, jsonHeaders)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, target)
+}
+
+// getRaw: Performs a GET against a forge and returns the response body, for raw file downloads
+func getRaw(forgeName, apiURL string, headers map[string]string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, apiURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		reset := time.Now().Add(time.Minute)
+		if unix, err := strconv.ParseInt(resp.Header.Get("RateLimit-Reset"), 10, 64); err == nil {
+			reset = time.Unix(unix, 0)
+		} else if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+			reset = time.Now().Add(time.Duration(seconds) * time.Second)
+		}
+		return nil, &RateLimitError{Forge: forgeName, Reset: reset}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s API returned status %d for %s", forgeName, resp.StatusCode, apiURL)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// --- Routing ---
+
+// ForgeConfig is one entry of the forge configuration file
+type ForgeConfig struct {
+	Host     string `json:"host"`      // Host as it appears in repository URLs, e.g. git.example.org
+	Type     string `json:"type"`      // github, gitlab, gitea (also Forgejo) or bitbucket
+	BaseURL  string `json:"base_url"`  // Web root of the instance; defaults to https://<host>
+	TokenEnv string `json:"token_env"` // Environment variable holding the access token
+}
+
+type forgeConfigFile struct {
+	Forges []ForgeConfig `json:"forges"`
+}
+
+// Router picks the forge implementation for a repository host
+type Router struct {
+	github     *GitHub          // github.com
+	configured *GitHub          // The client NewRouter was given: github.com or a GitHub Enterprise Server
+	forges     map[string]Forge // Keyed by lower-case host
+}
+
+// NewRouter: Routes github.com (or the GitHub Enterprise host of the client) to the given client and
+// every other host to the forge configured for it. With an enterprise client, github.com keeps a
+// client of its own (see newPublicGitHubClient), so public dependencies are not looked up on the
+// enterprise server.
+// Built in: gitlab.com (GITLAB_TOKEN), codeberg.org (Forgejo, GITEA_TOKEN) and bitbucket.org
+// (BITBUCKET_TOKEN). Further hosts come from GITLAB_BASE_URLS (comma-separated GitLab base URLs)
+// and from the JSON file named by FORGE_CONFIG (default forges.json, see forges.example.json).
+func NewRouter(client *github.Client) *Router {
+	configured := NewGitHub(client)
+	router := &Router{
+		github:     configured,
+		configured: configured,
+		forges:     make(map[string]Forge),
+	}
+	// With GITHUB_API_URL set, repositories on the enterprise server use the given client
+	if host := strings.ToLower(configured.Host()); host != githubHost {
+		router.github = NewGitHub(newPublicGitHubClient())
+		router.forges[host] = configured
+	}
+
+	router.add(ForgeConfig{Host: "gitlab.com", Type: "gitlab", TokenEnv: "GITLAB_TOKEN"})
+	router.add(ForgeConfig{Host: "codeberg.org", Type: "gitea", TokenEnv: "GITEA_TOKEN"})
+	router.add(ForgeConfig{Host: "bitbucket.org", Type: "bitbucket", TokenEnv: "BITBUCKET_TOKEN"})
+	for _, baseURL := range strings.Split(os.Getenv("GITLAB_BASE_URLS"), ",") {
+		if baseURL = strings.TrimSpace(baseURL); baseURL != "" {
+			router.add(ForgeConfig{Type: "gitlab", BaseURL: baseURL, TokenEnv: "GITLAB_TOKEN"})
+		}
+	}
+
+	configFile := os.Getenv("FORGE_CONFIG")
+	if configFile == "" {
+		configFile = "forges.json"
+	}
+	configs, err := readForgeConfig(configFile)
+	if err != nil {
+		fmt.Printf("⚠️ Warning: %v\n", err)
+	}
+	for _, config := range configs {
+		router.add(config)
+	}
+	return router
+}
+
+// readForgeConfig: Reads the forge configuration file; a missing file is not an error
+func readForgeConfig(filename string) ([]ForgeConfig, error) {
+	data, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading forge config %s: %w", filename, err)
+	}
+
+	var config forgeConfigFile
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("error parsing forge config %s: %w", filename, err)
+	}
+	return config.Forges, nil
+}
+
+// add: Registers the forge described by config under its host
+func (r *Router) add(config ForgeConfig) {
+	baseURL := config.BaseURL
+	if baseURL == "" {
+		baseURL = "https://" + config.Host
+	}
+	if !strings.Contains(baseURL, "://") {
+		baseURL = "https://" + baseURL
+	}
+	parsed, err := url.Parse(baseURL)
+	if err != nil || parsed.Host == "" {
+		fmt.Printf("⚠️ Warning: Ignoring forge with invalid base URL '%s'\n", baseURL)
+		return
+	}
+	host := strings.ToLower(config.Host)
+	if host == "" {
+		host = strings.ToLower(parsed.Host)
+	}
+	token := ""
+	if config.TokenEnv != "" {
+		token = os.Getenv(config.TokenEnv)
+	}
+
+	switch strings.ToLower(config.Type) {
+	case "github":
+		// Extra hostnames (e.g. a GHE web alias) for the configured GitHub client
+		r.forges[host] = r.configured
+	case "gitlab":
+		r.forges[host] = NewGitLab(baseURL, token)
+	case "gitea", "forgejo":
+		r.forges[host] = NewGitea(baseURL, token)
+	case "bitbucket":
+		r.forges[host] = NewBitbucket(token)
+	default:
+		fmt.Printf("⚠️ Warning: Ignoring forge %s with unknown type '%s'\n", host, config.Type)
+	}
+}
+
+// For returns the forge hosting repo, or an error for hosts no forge is configured for
+func (r *Router) For(repo Repository) (Forge, error) {
+	host := strings.ToLower(repo.Host)
+	switch host {
+	case "":
+		return r.configured, nil
+	case githubHost:
+		return r.github, nil
+	}
+	if forge, ok := r.forges[host]; ok {
+		return forge, nil
+	}
+	// Unlisted hosts named like a GitLab or Gitea instance (gitlab.example.com) are assumed to be one
+	switch {
+	case strings.HasPrefix(host, "gitlab."):
+		r.add(ForgeConfig{Host: host, Type: "gitlab", TokenEnv: "GITLAB_TOKEN"})
+		return r.forges[host], nil
+	case strings.HasPrefix(host, "gitea.") || strings.HasPrefix(host, "forgejo."):
+		r.add(ForgeConfig{Host: host, Type: "gitea", TokenEnv: "GITEA_TOKEN"})
+		return r.forges[host], nil
+	}
+	return nil, fmt.Errorf("unsupported forge host %s (add it to the forge config)", repo.Host)
+}
+
+// Resolve: Finds the forge for a repository URL as written in a manifest. Local repositories and
+// hosts without a configured forge are treated as plain git servers, whose tags are read with
+// the smart HTTP protocol instead of an API.
+func (r *Router) Resolve(rawURL string) (Forge, Repository, error) {
+	if isLocalRepo(rawURL) {
+		path := strings.TrimSuffix(strings.TrimPrefix(rawURL, "file://"), "/")
+		return NewGitRemote(rawURL), Repository{Name: strings.TrimSuffix(filepath.Base(path), ".git")}, nil
+	}
+
+	repo, ok := ParseRepoURL(rawURL)
+	if !ok {
+		return nil, Repository{}, fmt.Errorf("unrecognised repository URL %s", rawURL)
+	}
+	source, err := r.For(repo)
+	if err != nil {
+		return NewGitRemote(rawURL), repo, nil
+	}
+	return source, repo, nil
+}
+
+// Prefetch: Bulk-loads repository metadata on every forge that supports it, so the per-dependency
+// lookups that follow are served from memory. Failures only cost the bulk optimisation.
+func (r *Router) Prefetch(repos []Repository) {
+	byForge := make(map[Prefetcher][]Repository)
+	for _, repo := range repos {
+		source, err := r.For(repo)
+		if err != nil {
+			continue
+		}
+		if prefetcher, ok := source.(Prefetcher); ok {
+			byForge[prefetcher] = append(byForge[prefetcher], repo)
+		}
+	}
+
+	for prefetcher, batch := range byForge {
+		fmt.Printf("Prefetching metadata for %d repositories...\n", len(batch))
+		if err := prefetcher.Prefetch(batch); err != nil {
+			fmt.Printf("⚠️ Warning: Bulk metadata lookup failed (%v). Falling back to per-repository requests.\n", err)
+		}
+	}
+}
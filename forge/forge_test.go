@@ -0,0 +1,95 @@
+package forge
+
+import (
+	"os"
+	"testing"
+
+	"github.com/google/go-github/v62/github"
+)
+
+func TestRouterGitHubHosts(t *testing.T) {
+	t.Setenv("FORGE_CONFIG", "testdata/missing.json")
+	enterprise, err := github.NewClient(nil).WithEnterpriseURLs("https://ghe.example.com/api/v3/", "https://ghe.example.com/api/v3/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		client *github.Client
+		host   string
+		want   string
+	}{
+		{"github.com client", github.NewClient(nil), "github.com", "github.com"},
+		{"enterprise client, public repository", enterprise, "github.com", "github.com"},
+		{"enterprise client, enterprise repository", enterprise, "GHE.example.com", "ghe.example.com"},
+		{"enterprise client, repository without host", enterprise, "", "ghe.example.com"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			source, err := NewRouter(test.client).For(Repository{Host: test.host, Owner: "acme", Name: "lib"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			gh, ok := source.(*GitHub)
+			if !ok {
+				t.Fatalf("got %s, want GitHub", source.Name())
+			}
+			if gh.Host() != test.want {
+				t.Errorf("host %q routed to the client for %s, want %s", test.host, gh.Host(), test.want)
+			}
+		})
+	}
+}
+
+func TestParseRepoURL(t *testing.T) {
+	// A directory named like owner/repo must not turn the shorthand into a local path
+	t.Chdir(t.TempDir())
+	if err := os.MkdirAll("acme/lib", 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		raw  string
+		want Repository
+		ok   bool
+	}{
+		{"acme/lib", Repository{Host: "github.com", Owner: "acme", Name: "lib"}, true},
+		{"git+https://github.com/acme/lib.git#v1.2.0", Repository{Host: "github.com", Owner: "acme", Name: "lib"}, true},
+		{"https://www.github.com/acme/lib/tree/main/docs", Repository{Host: "github.com", Owner: "acme", Name: "lib"}, true},
+		{"git@gitlab.example.com:group/sub/project.git", Repository{Host: "gitlab.example.com", Owner: "group/sub", Name: "project"}, true},
+		{"https://gitlab.com/group/project/-/releases", Repository{Host: "gitlab.com", Owner: "group", Name: "project"}, true},
+		{"bitbucket:team/repo", Repository{Host: "bitbucket.org", Owner: "team", Name: "repo"}, true},
+		{"file:///srv/git/lib.git", Repository{}, false},
+		{"./vendor/lib", Repository{}, false},
+		{"/srv/git/lib.git", Repository{}, false},
+		{"lib", Repository{}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.raw, func(t *testing.T) {
+			got, ok := ParseRepoURL(test.raw)
+			if ok != test.ok || got != test.want {
+				t.Errorf("got %+v (%v), want %+v (%v)", got, ok, test.want, test.ok)
+			}
+		})
+	}
+}
+
+func TestResolveLocalRepository(t *testing.T) {
+	t.Setenv("FORGE_CONFIG", "testdata/missing.json")
+	dir := t.TempDir()
+	source, repo, err := NewRouter(github.NewClient(nil)).Resolve(dir + "/lib.git")
+	if err == nil {
+		t.Fatalf("missing directory resolved to %s", source.Name())
+	}
+	if err := os.Mkdir(dir+"/lib.git", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	source, repo, err = NewRouter(github.NewClient(nil)).Resolve(dir + "/lib.git")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := source.(*GitRemote); !ok || repo.Name != "lib" {
+		t.Errorf("got %s %+v, want the local git remote lib", source.Name(), repo)
+	}
+}
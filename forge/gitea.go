@@ -0,0 +1,193 @@
+package forge
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Gitea fulfils Forge through the Gitea REST API (v1), which Forgejo (e.g. codeberg.org) shares
+type Gitea struct {
+	baseURL string
+	token   string
+}
+
+type giteaRepository struct {
+	Archived      bool   `json:"archived"`
+	DefaultBranch string `json:"default_branch"`
+}
+
+type giteaCommit struct {
+	SHA    string `json:"sha"`
+	Commit struct {
+		Committer struct {
+			Date time.Time `json:"date"`
+		} `json:"committer"`
+	} `json:"commit"`
+}
+
+type giteaRelease struct {
+	TagName     string    `json:"tag_name"`
+	Name        string    `json:"name"`
+	Body        string    `json:"body"`
+	Draft       bool      `json:"draft"`
+	PublishedAt time.Time `json:"published_at"`
+}
+
+type giteaTag struct {
+	Name string `json:"name"`
+}
+
+func NewGitea(baseURL, token string) *Gitea {
+	return &Gitea{baseURL: strings.TrimSuffix(baseURL, "/"), token: token}
+}
+
+func (g *Gitea) Name() string {
+	return "Gitea"
+}
+
+func (g *Gitea) repoURL(repo Repository, suffix string) string {
+	return fmt.Sprintf("%s/api/v1/repos/%s/%s%s", g.baseURL, url.PathEscape(repo.Owner), url.PathEscape(repo.Name), suffix)
+}
+
+func (g *Gitea) headers() map[string]string {
+	headers := map[string]string{}
+	if g.token != "" {
+		headers["Authorization"] = "token " + g.token
+	}
+	return headers
+}
+
+// get: Performs an authenticated GET and decodes the JSON response into target
+func (g *Gitea) get(apiURL string, target interface{}) error {
+	return getJSON(g.Name(), apiURL, g.headers(), target)
+}
+
+func (g *Gitea) IsArchived(repo Repository) (bool, error) {
+	var details giteaRepository
+	if err := g.get(g.repoURL(repo, ""), &details); err != nil {
+		return false, err
+	}
+	return details.Archived, nil
+}
+
+func (g *Gitea) LatestRelease(repo Repository) (Release, error) {
+	var release giteaRelease
+	if err := g.get(g.repoURL(repo, "/releases/latest"), &release); err != nil {
+		return Release{}, err
+	}
+	return Release{TagName: release.TagName, Name: release.Name, Body: release.Body, PublishedAt: release.PublishedAt}, nil
+}
+
+func (g *Gitea) ListReleases(repo Repository) ([]Release, error) {
+	var releases []giteaRelease
+	if err := g.get(g.repoURL(repo, "/releases?limit=30"), &releases); err != nil {
+		return nil, err
+	}
+	return publishedGiteaReleases(releases), nil
+}
+
+// ReleasesUntil: Gitea caps pages at 50 entries by default
+func (g *Gitea) ReleasesUntil(repo Repository, reached func(Release) bool) ([]Release, error) {
+	var result []Release
+	for page := 1; page <= maxReleasePages; page++ {
+		var releases []giteaRelease
+		if err := g.get(g.repoURL(repo, fmt.Sprintf("/releases?limit=50&page=%d", page)), &releases); err != nil {
+			return result, err
+		}
+		batch := publishedGiteaReleases(releases)
+		result = append(result, batch...)
+		if allReached(batch, reached) || len(releases) < 50 {
+			break
+		}
+	}
+	return result, nil
+}
+
+// publishedGiteaReleases: Converts releases, skipping drafts
+func publishedGiteaReleases(releases []giteaRelease) []Release {
+	var result []Release
+	for _, release := range releases {
+		if release.Draft {
+			continue
+		}
+		result = append(result, Release{TagName: release.TagName, Name: release.Name, Body: release.Body, PublishedAt: release.PublishedAt})
+	}
+	return result
+}
+
+func (g *Gitea) ListTags(repo Repository) ([]string, error) {
+	var tags []giteaTag
+	if err := g.get(g.repoURL(repo, "/tags?limit=30"), &tags); err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+	}
+	return names, nil
+}
+
+// ReadFile: The raw endpoint serves the default branch when no ref is given
+func (g *Gitea) ReadFile(repo Repository, path string) ([]byte, error) {
+	return getRaw(g.Name(), g.repoURL(repo, "/raw/"+path), g.headers())
+}
+
+func (g *Gitea) WebURL(repo Repository) string {
+	return fmt.Sprintf("%s/%s", g.baseURL, repo.Path())
+}
+
+func (g *Gitea) ReleaseURL(repo Repository, tag string) string {
+	return fmt.Sprintf("%s/releases/tag/%s", g.WebURL(repo), url.PathEscape(tag))
+}
+
+func (g *Gitea) TagsURL(repo Repository) string {
+	return g.WebURL(repo) + "/tags"
+}
+
+// --- Commit History ---
+
+func (g *Gitea) DefaultBranch(repo Repository) (string, error) {
+	var details giteaRepository
+	if err := g.get(g.repoURL(repo, ""), &details); err != nil {
+		return "", err
+	}
+	return details.DefaultBranch, nil
+}
+
+// Commit: The commit list accepts branches, tags and SHAs alike
+func (g *Gitea) Commit(repo Repository, ref string) (Commit, error) {
+	var commits []giteaCommit
+	if err := g.get(g.repoURL(repo, "/commits?limit=1&stat=false&sha="+url.QueryEscape(ref)), &commits); err != nil {
+		return Commit{}, err
+	}
+	if len(commits) == 0 {
+		return Commit{}, fmt.Errorf("ref %s not found", ref)
+	}
+	return Commit{SHA: commits[0].SHA, Date: commits[0].Commit.Committer.Date}, nil
+}
+
+// Compare: Gitea (1.22+) counts the commits in base...head, so behind is the comparison the other way round
+func (g *Gitea) Compare(repo Repository, base, head string) (Comparison, error) {
+	ahead, err := g.countCommits(repo, base, head)
+	if err != nil {
+		return Comparison{}, err
+	}
+	behind, err := g.countCommits(repo, head, base)
+	if err != nil {
+		return Comparison{}, err
+	}
+	return Comparison{AheadBy: ahead, BehindBy: behind}, nil
+}
+
+func (g *Gitea) countCommits(repo Repository, base, head string) (int, error) {
+	var comparison struct {
+		TotalCommits int `json:"total_commits"`
+	}
+	if err := g.get(g.repoURL(repo, "/compare/"+url.PathEscape(base)+"..."+url.PathEscape(head)), &comparison); err != nil {
+		return 0, err
+	}
+	return comparison.TotalCommits, nil
+}
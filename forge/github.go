@@ -0,0 +1,199 @@
+package forge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v62/github"
+)
+
+// GitHub fulfils Forge through the go-github REST client, for github.com or a GitHub Enterprise Server
+type GitHub struct {
+	client   *github.Client
+	webURL   string                   // https://github.com, or the web root of the enterprise server
+	metadata map[string]*repoMetadata // Filled by Prefetch (GraphQL); REST is the fallback
+}
+
+func NewGitHub(client *github.Client) *GitHub {
+	webURL := "https://" + githubHost
+	if client != nil && client.BaseURL.Host != "api.github.com" {
+		// Enterprise API lives under https://<host>/api/v3/
+		webURL = client.BaseURL.Scheme + "://" + client.BaseURL.Host
+	}
+	return &GitHub{client: client, webURL: webURL}
+}
+
+// Host is the host repository URLs on this GitHub use
+func (g *GitHub) Host() string {
+	return strings.TrimPrefix(strings.TrimPrefix(g.webURL, "https://"), "http://")
+}
+
+func (g *GitHub) Name() string {
+	return "GitHub"
+}
+
+// rateLimit: Converts go-github's rate limit errors into the forge-neutral RateLimitError
+func (g *GitHub) rateLimit(err error) error {
+	var rateErr *github.RateLimitError
+	if errors.As(err, &rateErr) {
+		return &RateLimitError{Forge: g.Name(), Reset: rateErr.Rate.Reset.Time}
+	}
+	var abuseErr *github.AbuseRateLimitError
+	if errors.As(err, &abuseErr) && abuseErr.RetryAfter != nil {
+		return &RateLimitError{Forge: g.Name(), Reset: time.Now().Add(*abuseErr.RetryAfter)}
+	}
+	return err
+}
+
+func (g *GitHub) IsArchived(repo Repository) (bool, error) {
+	if metadata, ok := g.cached(repo); ok {
+		return metadata.Archived, nil
+	}
+	details, _, err := g.client.Repositories.Get(context.Background(), repo.Owner, repo.Name)
+	if err != nil {
+		return false, g.rateLimit(err)
+	}
+	return details.GetArchived(), nil
+}
+
+func (g *GitHub) LatestRelease(repo Repository) (Release, error) {
+	if metadata, ok := g.cached(repo); ok {
+		if metadata.LatestRelease == nil {
+			return Release{}, fmt.Errorf("no releases found")
+		}
+		return *metadata.LatestRelease, nil
+	}
+	release, _, err := g.client.Repositories.GetLatestRelease(context.Background(), repo.Owner, repo.Name)
+	if err != nil {
+		return Release{}, g.rateLimit(err)
+	}
+	if release == nil {
+		return Release{}, fmt.Errorf("no releases found")
+	}
+	return githubRelease(release), nil
+}
+
+func (g *GitHub) ListReleases(repo Repository) ([]Release, error) {
+	if metadata, ok := g.cached(repo); ok {
+		return metadata.Releases, nil
+	}
+	releases, _, err := g.client.Repositories.ListReleases(context.Background(), repo.Owner, repo.Name, &github.ListOptions{
+		PerPage: 30,
+	})
+	if err != nil {
+		return nil, g.rateLimit(err)
+	}
+
+	var result []Release
+	for _, release := range releases {
+		result = append(result, githubRelease(release))
+	}
+	return result, nil
+}
+
+func (g *GitHub) ListTags(repo Repository) ([]string, error) {
+	if metadata, ok := g.cached(repo); ok {
+		return metadata.Tags, nil
+	}
+	tags, _, err := g.client.Repositories.ListTags(context.Background(), repo.Owner, repo.Name, &github.ListOptions{PerPage: 30})
+	if err != nil {
+		return nil, g.rateLimit(err)
+	}
+
+	var names []string
+	for _, tag := range tags {
+		names = append(names, tag.GetName())
+	}
+	return names, nil
+}
+
+func (g *GitHub) WebURL(repo Repository) string {
+	return fmt.Sprintf("%s/%s", g.webURL, repo.Path())
+}
+
+func (g *GitHub) ReleaseURL(repo Repository, tag string) string {
+	return fmt.Sprintf("%s/releases/tag/%s", g.WebURL(repo), tag)
+}
+
+func (g *GitHub) TagsURL(repo Repository) string {
+	return g.WebURL(repo) + "/tags"
+}
+
+// githubRelease: Converts a go-github release
+func githubRelease(release *github.RepositoryRelease) Release {
+	return Release{TagName: release.GetTagName(), Name: release.GetName(), Body: release.GetBody(), PublishedAt: release.GetPublishedAt().Time}
+}
+
+// --- Commit History ---
+
+func (g *GitHub) DefaultBranch(repo Repository) (string, error) {
+	details, _, err := g.client.Repositories.Get(context.Background(), repo.Owner, repo.Name)
+	if err != nil {
+		return "", g.rateLimit(err)
+	}
+	return details.GetDefaultBranch(), nil
+}
+
+func (g *GitHub) Commit(repo Repository, ref string) (Commit, error) {
+	commit, _, err := g.client.Repositories.GetCommit(context.Background(), repo.Owner, repo.Name, ref, nil)
+	if err != nil {
+		return Commit{}, g.rateLimit(err)
+	}
+	return Commit{SHA: commit.GetSHA(), Date: commit.GetCommit().GetCommitter().GetDate().Time}, nil
+}
+
+// Compare: Only the counts are needed, so a single commit of the comparison is requested
+func (g *GitHub) Compare(repo Repository, base, head string) (Comparison, error) {
+	comparison, _, err := g.client.Repositories.CompareCommits(context.Background(), repo.Owner, repo.Name, base, head, &github.ListOptions{PerPage: 1})
+	if err != nil {
+		return Comparison{}, g.rateLimit(err)
+	}
+	return Comparison{AheadBy: comparison.GetAheadBy(), BehindBy: comparison.GetBehindBy()}, nil
+}
+
+// --- Release History ---
+
+// ReleasesUntil: The prefetched releases suffice when they already reach back far enough
+func (g *GitHub) ReleasesUntil(repo Repository, reached func(Release) bool) ([]Release, error) {
+	if metadata, ok := g.cached(repo); ok && reachedBack(metadata.Releases, reached) {
+		return metadata.Releases, nil
+	}
+
+	var result []Release
+	opts := &github.ListOptions{PerPage: 100}
+	for page := 0; page < maxReleasePages; page++ {
+		releases, resp, err := g.client.Repositories.ListReleases(context.Background(), repo.Owner, repo.Name, opts)
+		if err != nil {
+			return result, g.rateLimit(err)
+		}
+
+		var batch []Release
+		for _, release := range releases {
+			if release.GetDraft() {
+				continue
+			}
+			batch = append(batch, githubRelease(release))
+		}
+		result = append(result, batch...)
+		if allReached(batch, reached) || resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return result, nil
+}
+
+func (g *GitHub) ReadFile(repo Repository, path string) ([]byte, error) {
+	file, _, _, err := g.client.Repositories.GetContents(context.Background(), repo.Owner, repo.Name, path, nil)
+	if err != nil {
+		return nil, g.rateLimit(err)
+	}
+	if file == nil {
+		return nil, fmt.Errorf("%s is a directory", path)
+	}
+	content, err := file.GetContent()
+	return []byte(content), err
+}
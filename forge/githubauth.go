@@ -0,0 +1,214 @@
+package forge
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v62/github"
+	"golang.org/x/oauth2"
+)
+
+// --- GitHub Client Setup ---
+
+// NewGitHubClient: Creates the GitHub client shared by all audits.
+//
+//   - GITHUB_API_URL points the client at a GitHub Enterprise Server API (e.g. https://ghe.example.com/api/v3/);
+//     github.com repositories are then read with GITHUB_COM_TOKEN (see newPublicGitHubClient).
+//   - GITHUB_APP_ID with GITHUB_APP_PRIVATE_KEY (PEM) or GITHUB_APP_PRIVATE_KEY_PATH authenticates as a
+//     GitHub App installation (GITHUB_APP_INSTALLATION_ID, optional when the app has a single installation);
+//     installation tokens are refreshed automatically before they expire.
+//   - Otherwise GITHUB_TOKEN is used as a personal access token, or the client runs unauthenticated.
+func NewGitHubClient() *github.Client {
+	apiURL := os.Getenv("GITHUB_API_URL")
+
+	var httpClient *http.Client
+	if appID := os.Getenv("GITHUB_APP_ID"); appID != "" {
+		source, err := newAppTokenSource(apiURL, appID)
+		if err != nil {
+			fmt.Printf("⚠️ Warning: GitHub App authentication failed (%v). Falling back to GITHUB_TOKEN.\n", err)
+		} else {
+			httpClient = oauth2.NewClient(context.Background(), oauth2.ReuseTokenSource(nil, source))
+		}
+	}
+	if httpClient == nil {
+		if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+			httpClient = oauth2.NewClient(context.Background(), oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token}))
+		} else {
+			fmt.Println("⚠️ Warning: GITHUB_TOKEN not set. Running unauthenticated (low rate limit).")
+		}
+	}
+
+	client := github.NewClient(httpClient)
+	if apiURL != "" {
+		enterprise, err := client.WithEnterpriseURLs(apiURL, apiURL)
+		if err != nil {
+			fmt.Printf("⚠️ Warning: Invalid GITHUB_API_URL '%s': %v. Using github.com.\n", apiURL, err)
+			return client
+		}
+		client = enterprise
+	}
+	return client
+}
+
+// newPublicGitHubClient: The github.com client the router uses next to a GitHub Enterprise Server
+// client. GITHUB_TOKEN and the app credentials belong to the enterprise server, so github.com is
+// queried with GITHUB_COM_TOKEN when set and unauthenticated otherwise.
+func newPublicGitHubClient() *github.Client {
+	if token := os.Getenv("GITHUB_COM_TOKEN"); token != "" {
+		return github.NewClient(oauth2.NewClient(context.Background(), oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})))
+	}
+	return github.NewClient(nil)
+}
+
+// --- GitHub App Authentication ---
+
+// appTokenSource exchanges a JWT signed with the app's private key for installation tokens
+type appTokenSource struct {
+	apiURL         string
+	appID          string
+	installationID string
+	key            *rsa.PrivateKey
+}
+
+type installationToken struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func newAppTokenSource(apiURL, appID string) (*appTokenSource, error) {
+	if apiURL == "" {
+		apiURL = "https://api.github.com/"
+	}
+	if !strings.HasSuffix(apiURL, "/") {
+		apiURL += "/"
+	}
+
+	pemData := []byte(os.Getenv("GITHUB_APP_PRIVATE_KEY"))
+	if keyPath := os.Getenv("GITHUB_APP_PRIVATE_KEY_PATH"); len(pemData) == 0 && keyPath != "" {
+		data, err := os.ReadFile(keyPath)
+		if err != nil {
+			return nil, fmt.Errorf("error reading private key: %w", err)
+		}
+		pemData = data
+	}
+	key, err := parsePrivateKey(pemData)
+	if err != nil {
+		return nil, err
+	}
+
+	source := &appTokenSource{apiURL: apiURL, appID: appID, key: key, installationID: os.Getenv("GITHUB_APP_INSTALLATION_ID")}
+	if source.installationID == "" {
+		if source.installationID, err = source.findInstallation(); err != nil {
+			return nil, err
+		}
+	}
+	return source, nil
+}
+
+// parsePrivateKey: Accepts the PKCS#1 key GitHub generates as well as PKCS#8
+func parsePrivateKey(pemData []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(pemData)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM private key found (set GITHUB_APP_PRIVATE_KEY or GITHUB_APP_PRIVATE_KEY_PATH)")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key is not an RSA key")
+	}
+	return key, nil
+}
+
+// signJWT: Creates the short-lived RS256 JWT identifying the app itself
+func (s *appTokenSource) signJWT() (string, error) {
+	now := time.Now()
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","typ":"JWT"}`))
+	claims, err := json.Marshal(map[string]interface{}{
+		"iat": now.Add(-time.Minute).Unix(), // Allow for clock drift
+		"exp": now.Add(9 * time.Minute).Unix(),
+		"iss": s.appID,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := header + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// appRequest: Calls an /app endpoint authenticated with the app JWT
+func (s *appTokenSource) appRequest(method, path string, target interface{}) error {
+	jwt, err := s.signJWT()
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(method, s.apiURL+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+jwt)
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("GitHub App API returned status %d for %s", resp.StatusCode, path)
+	}
+	return json.NewDecoder(resp.Body).Decode(target)
+}
+
+// findInstallation: Uses the app's only installation when no installation ID is configured
+func (s *appTokenSource) findInstallation() (string, error) {
+	var installations []struct {
+		ID      int64 `json:"id"`
+		Account struct {
+			Login string `json:"login"`
+		} `json:"account"`
+	}
+	if err := s.appRequest(http.MethodGet, "app/installations", &installations); err != nil {
+		return "", err
+	}
+	if len(installations) != 1 {
+		return "", fmt.Errorf("the app has %d installations; set GITHUB_APP_INSTALLATION_ID", len(installations))
+	}
+	fmt.Printf("Using GitHub App installation for %s.\n", installations[0].Account.Login)
+	return strconv.FormatInt(installations[0].ID, 10), nil
+}
+
+// Token: Requests a new installation token (valid for one hour); oauth2.ReuseTokenSource
+// calls it again once the previous token is about to expire
+func (s *appTokenSource) Token() (*oauth2.Token, error) {
+	var token installationToken
+	if err := s.appRequest(http.MethodPost, "app/installations/"+s.installationID+"/access_tokens", &token); err != nil {
+		return nil, err
+	}
+	return &oauth2.Token{AccessToken: token.Token, Expiry: token.ExpiresAt}, nil
+}
@@ -0,0 +1,198 @@
+package forge
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// GitLab fulfils Forge through the GitLab REST API (v4), for gitlab.com and self-hosted instances
+type GitLab struct {
+	baseURL string
+	token   string
+}
+
+type gitlabProject struct {
+	Archived      bool   `json:"archived"`
+	DefaultBranch string `json:"default_branch"`
+}
+
+type gitlabCommit struct {
+	ID            string    `json:"id"`
+	CommittedDate time.Time `json:"committed_date"`
+}
+
+type gitlabRelease struct {
+	TagName         string    `json:"tag_name"`
+	Name            string    `json:"name"`
+	Description     string    `json:"description"`
+	UpcomingRelease bool      `json:"upcoming_release"`
+	ReleasedAt      time.Time `json:"released_at"`
+}
+
+type gitlabTag struct {
+	Name string `json:"name"`
+}
+
+func NewGitLab(baseURL, token string) *GitLab {
+	return &GitLab{baseURL: strings.TrimSuffix(baseURL, "/"), token: token}
+}
+
+func (g *GitLab) Name() string {
+	return "GitLab"
+}
+
+// projectURL: Builds an API URL; GitLab accepts the URL-encoded "group/subgroup/project" path as project id
+func (g *GitLab) projectURL(repo Repository, suffix string) string {
+	id := strings.ReplaceAll(url.PathEscape(repo.Path()), "/", "%2F")
+	return fmt.Sprintf("%s/api/v4/projects/%s%s", g.baseURL, id, suffix)
+}
+
+func (g *GitLab) headers() map[string]string {
+	headers := map[string]string{}
+	if g.token != "" {
+		headers["PRIVATE-TOKEN"] = g.token
+	}
+	return headers
+}
+
+// get: Performs an authenticated GET and decodes the JSON response into target
+func (g *GitLab) get(apiURL string, target interface{}) error {
+	return getJSON(g.Name(), apiURL, g.headers(), target)
+}
+
+func (g *GitLab) IsArchived(repo Repository) (bool, error) {
+	var project gitlabProject
+	if err := g.get(g.projectURL(repo, ""), &project); err != nil {
+		return false, err
+	}
+	return project.Archived, nil
+}
+
+// LatestRelease: GitLab orders releases by release date, newest first; upcoming releases are skipped
+func (g *GitLab) LatestRelease(repo Repository) (Release, error) {
+	releases, err := g.ListReleases(repo)
+	if err != nil {
+		return Release{}, err
+	}
+	if len(releases) == 0 {
+		return Release{}, fmt.Errorf("no releases found")
+	}
+	return releases[0], nil
+}
+
+func (g *GitLab) ListReleases(repo Repository) ([]Release, error) {
+	var releases []gitlabRelease
+	if err := g.get(g.projectURL(repo, "/releases?per_page=30"), &releases); err != nil {
+		return nil, err
+	}
+	return publishedGitLabReleases(releases), nil
+}
+
+func (g *GitLab) ReleasesUntil(repo Repository, reached func(Release) bool) ([]Release, error) {
+	var result []Release
+	for page := 1; page <= maxReleasePages; page++ {
+		var releases []gitlabRelease
+		if err := g.get(g.projectURL(repo, fmt.Sprintf("/releases?per_page=100&page=%d", page)), &releases); err != nil {
+			return result, err
+		}
+		batch := publishedGitLabReleases(releases)
+		result = append(result, batch...)
+		if allReached(batch, reached) || len(releases) < 100 {
+			break
+		}
+	}
+	return result, nil
+}
+
+// publishedGitLabReleases: Converts releases, skipping upcoming ones
+func publishedGitLabReleases(releases []gitlabRelease) []Release {
+	var result []Release
+	for _, release := range releases {
+		if release.UpcomingRelease {
+			continue
+		}
+		result = append(result, Release{TagName: release.TagName, Name: release.Name, Body: release.Description, PublishedAt: release.ReleasedAt})
+	}
+	return result
+}
+
+func (g *GitLab) ListTags(repo Repository) ([]string, error) {
+	var tags []gitlabTag
+	if err := g.get(g.projectURL(repo, "/repository/tags?per_page=30"), &tags); err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+	}
+	return names, nil
+}
+
+func (g *GitLab) ReadFile(repo Repository, path string) ([]byte, error) {
+	branch, err := g.DefaultBranch(repo)
+	if err != nil {
+		return nil, err
+	}
+	filePath := strings.ReplaceAll(url.PathEscape(path), "/", "%2F")
+	return getRaw(g.Name(), g.projectURL(repo, "/repository/files/"+filePath+"/raw?ref="+url.QueryEscape(branch)), g.headers())
+}
+
+func (g *GitLab) WebURL(repo Repository) string {
+	return fmt.Sprintf("%s/%s", g.baseURL, repo.Path())
+}
+
+func (g *GitLab) ReleaseURL(repo Repository, tag string) string {
+	return fmt.Sprintf("%s/-/releases/%s", g.WebURL(repo), url.PathEscape(tag))
+}
+
+func (g *GitLab) TagsURL(repo Repository) string {
+	return g.WebURL(repo) + "/-/tags"
+}
+
+// --- Commit History ---
+
+func (g *GitLab) DefaultBranch(repo Repository) (string, error) {
+	var project gitlabProject
+	if err := g.get(g.projectURL(repo, ""), &project); err != nil {
+		return "", err
+	}
+	return project.DefaultBranch, nil
+}
+
+func (g *GitLab) Commit(repo Repository, ref string) (Commit, error) {
+	var commit gitlabCommit
+	if err := g.get(g.projectURL(repo, "/repository/commits/"+url.PathEscape(ref)), &commit); err != nil {
+		return Commit{}, err
+	}
+	return Commit{SHA: commit.ID, Date: commit.CommittedDate}, nil
+}
+
+// Compare: GitLab only lists the commits in from...to, so behind is the comparison the other way round
+func (g *GitLab) Compare(repo Repository, base, head string) (Comparison, error) {
+	ahead, err := g.countCommits(repo, base, head)
+	if err != nil {
+		return Comparison{}, err
+	}
+	behind, err := g.countCommits(repo, head, base)
+	if err != nil {
+		return Comparison{}, err
+	}
+	return Comparison{AheadBy: ahead, BehindBy: behind}, nil
+}
+
+// countCommits: Counts the commits reachable from to but not from from
+func (g *GitLab) countCommits(repo Repository, from, to string) (int, error) {
+	var comparison struct {
+		Commits []struct {
+			ID string `json:"id"`
+		} `json:"commits"`
+	}
+	suffix := fmt.Sprintf("/repository/compare?from=%s&to=%s", url.QueryEscape(from), url.QueryEscape(to))
+	if err := g.get(g.projectURL(repo, suffix), &comparison); err != nil {
+		return 0, err
+	}
+	return len(comparison.Commits), nil
+}
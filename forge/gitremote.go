@@ -0,0 +1,248 @@
+package forge
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// GitRemote fulfils Forge for plain git servers, and as a token-free fallback for any forge:
+// tags are listed like `git ls-remote --tags`, over the smart HTTP protocol or from a local
+// repository on disk. Plain git has no releases, archiving or web pages.
+type GitRemote struct {
+	url string
+}
+
+func NewGitRemote(rawURL string) *GitRemote {
+	return &GitRemote{url: rawURL}
+}
+
+func (g *GitRemote) Name() string {
+	return "git"
+}
+
+func (g *GitRemote) IsArchived(repo Repository) (bool, error) {
+	return false, nil
+}
+
+func (g *GitRemote) LatestRelease(repo Repository) (Release, error) {
+	return Release{}, fmt.Errorf("plain git repositories have no releases")
+}
+
+func (g *GitRemote) ListReleases(repo Repository) ([]Release, error) {
+	return nil, nil
+}
+
+func (g *GitRemote) ListTags(repo Repository) ([]string, error) {
+	return ListRemoteTags(g.url)
+}
+
+func (g *GitRemote) ReleasesUntil(repo Repository, reached func(Release) bool) ([]Release, error) {
+	return nil, nil
+}
+
+// ReadFile: Only the work tree of a local repository can be read without the git object store
+func (g *GitRemote) ReadFile(repo Repository, path string) ([]byte, error) {
+	if !isLocalRepo(g.url) {
+		return nil, fmt.Errorf("plain git repositories cannot serve files")
+	}
+	return os.ReadFile(filepath.Join(strings.TrimPrefix(g.url, "file://"), path))
+}
+
+func (g *GitRemote) WebURL(repo Repository) string {
+	return g.url
+}
+
+func (g *GitRemote) ReleaseURL(repo Repository, tag string) string {
+	return g.url
+}
+
+func (g *GitRemote) TagsURL(repo Repository) string {
+	return g.url
+}
+
+// --- ls-remote ---
+
+// isLocalRepo reports whether rawURL names a repository on disk (file:// URL or path)
+func isLocalRepo(rawURL string) bool {
+	if strings.HasPrefix(rawURL, "file://") {
+		return true
+	}
+	if strings.Contains(rawURL, "://") || strings.Contains(rawURL, "@") {
+		return false
+	}
+	_, err := os.Stat(rawURL)
+	return err == nil
+}
+
+// ListRemoteTags: Lists the tag names of a repository without any API or token.
+// Local paths and file:// URLs are read from disk; everything else goes over smart HTTP.
+func ListRemoteTags(rawURL string) ([]string, error) {
+	if isLocalRepo(rawURL) {
+		return listLocalTags(strings.TrimPrefix(rawURL, "file://"))
+	}
+
+	httpURL := smartHTTPURL(rawURL)
+	tags, err := listHTTPTags(httpURL)
+	if err != nil && !strings.HasSuffix(httpURL, ".git") {
+		// Plain git servers (git http-backend, cgit) usually need the .git suffix
+		if withSuffix, suffixErr := listHTTPTags(httpURL + ".git"); suffixErr == nil {
+			return withSuffix, nil
+		}
+	}
+	return tags, err
+}
+
+// smartHTTPURL: Maps git://, ssh:// and scp-style URLs onto the https URL of the same repository
+func smartHTTPURL(rawURL string) string {
+	rawURL = strings.TrimPrefix(strings.Split(rawURL, "#")[0], "git+")
+	switch {
+	case strings.HasPrefix(rawURL, "http://") || strings.HasPrefix(rawURL, "https://"):
+	case strings.Contains(rawURL, "://"):
+		if parsed, err := url.Parse(rawURL); err == nil {
+			rawURL = "https://" + parsed.Hostname() + parsed.Path
+		}
+	case strings.Contains(rawURL, "@") && strings.Contains(rawURL, ":"):
+		userHost, path, _ := strings.Cut(rawURL, ":")
+		rawURL = "https://" + userHost[strings.LastIndex(userHost, "@")+1:] + "/" + strings.TrimPrefix(path, "/")
+	default:
+		rawURL = "https://" + rawURL
+	}
+	return strings.TrimSuffix(rawURL, "/")
+}
+
+// listHTTPTags: Reads the ref advertisement of the smart HTTP protocol (GET info/refs?service=git-upload-pack)
+func listHTTPTags(repoURL string) ([]string, error) {
+	req, err := http.NewRequest(http.MethodGet, repoURL+"/info/refs?service=git-upload-pack", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "git/2.0 (sbom)")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("git server returned status %d for %s", resp.StatusCode, repoURL)
+	}
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "application/x-git-upload-pack-advertisement") {
+		return nil, fmt.Errorf("%s does not speak the git smart HTTP protocol", repoURL)
+	}
+
+	var refs []string
+	reader := bufio.NewReader(resp.Body)
+	for {
+		line, flush, err := readPktLine(reader)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if flush || strings.HasPrefix(line, "# service=") {
+			continue
+		}
+		// "<sha> <refname>\x00<capabilities>" on the first ref, "<sha> <refname>" afterwards
+		line = strings.SplitN(strings.TrimSuffix(line, "\n"), "\x00", 2)[0]
+		if _, name, found := strings.Cut(line, " "); found {
+			refs = append(refs, name)
+		}
+	}
+	return tagNames(refs), nil
+}
+
+// readPktLine: Reads one pkt-line: a 4-digit hex length (including itself), then the payload; "0000" is a flush
+func readPktLine(reader *bufio.Reader) (string, bool, error) {
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return "", false, err
+	}
+	length, err := strconv.ParseUint(string(header), 16, 16)
+	if err != nil {
+		return "", false, fmt.Errorf("invalid pkt-line header %q", header)
+	}
+	if length == 0 {
+		return "", true, nil
+	}
+	if length < 4 {
+		return "", false, fmt.Errorf("invalid pkt-line length %d", length)
+	}
+	payload := make([]byte, length-4)
+	if _, err := io.ReadFull(reader, payload); err != nil {
+		return "", false, err
+	}
+	return string(payload), false, nil
+}
+
+// listLocalTags: Reads loose tag refs and packed-refs of a bare repository, or of the .git directory of a work tree
+func listLocalTags(path string) ([]string, error) {
+	gitDir := path
+	if info, err := os.Stat(filepath.Join(path, ".git")); err == nil && info.IsDir() {
+		gitDir = filepath.Join(path, ".git")
+	}
+	if _, err := os.Stat(filepath.Join(gitDir, "HEAD")); err != nil {
+		return nil, fmt.Errorf("%s is not a git repository", path)
+	}
+
+	var refs []string
+	tagsDir := filepath.Join(gitDir, "refs", "tags")
+	err := filepath.WalkDir(tagsDir, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if !d.IsDir() {
+			rel, _ := filepath.Rel(tagsDir, p)
+			refs = append(refs, "refs/tags/"+filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reading tags of %s: %w", path, err)
+	}
+
+	if file, err := os.Open(filepath.Join(gitDir, "packed-refs")); err == nil {
+		defer file.Close()
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if strings.HasPrefix(line, "#") || strings.HasPrefix(line, "^") {
+				continue
+			}
+			if _, name, found := strings.Cut(line, " "); found {
+				refs = append(refs, name)
+			}
+		}
+	}
+	return tagNames(refs), nil
+}
+
+// tagNames: Keeps refs/tags/* names, dropping peeled "^{}" entries and duplicates
+func tagNames(refs []string) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, ref := range refs {
+		if !strings.HasPrefix(ref, "refs/tags/") {
+			continue
+		}
+		name := strings.TrimSuffix(strings.TrimPrefix(ref, "refs/tags/"), "^{}")
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,115 @@
+package forge
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestReadPktLine(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		flush   bool
+		wantErr string
+	}{
+		{"payload", "000ahello\n", "hello\n", false, ""},
+		{"empty payload", "0004", "", false, ""},
+		{"flush", "0000", "", true, ""},
+		{"upper-case hex", "000Ahello\n", "hello\n", false, ""},
+		{"not hex", "00zzhello", "", false, "invalid pkt-line header"},
+		{"length below the header", "0003", "", false, "invalid pkt-line length 3"},
+		{"truncated payload", "0010short", "", false, io.ErrUnexpectedEOF.Error()},
+		{"truncated header", "00", "", false, io.ErrUnexpectedEOF.Error()},
+		{"end of stream", "", "", false, io.EOF.Error()},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			line, flush, err := readPktLine(bufio.NewReader(strings.NewReader(test.input)))
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Fatalf("got error %v, want %q", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if line != test.want || flush != test.flush {
+				t.Errorf("got %q (flush %v), want %q (flush %v)", line, flush, test.want, test.flush)
+			}
+		})
+	}
+}
+
+// pktLine: Frames a payload as a pkt-line
+func pktLine(payload string) string {
+	return fmt.Sprintf("%04x%s", len(payload)+4, payload)
+}
+
+func TestListHTTPTags(t *testing.T) {
+	const sha = "1111111111111111111111111111111111111111"
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        []string
+		wantErr     string
+	}{
+		{
+			name:        "advertisement",
+			contentType: "application/x-git-upload-pack-advertisement",
+			body: pktLine("# service=git-upload-pack\n") + "0000" +
+				pktLine(sha+" HEAD\x00multi_ack side-band-64k\n") +
+				pktLine(sha+" refs/heads/main\n") +
+				pktLine(sha+" refs/tags/v1.10.0\n") +
+				pktLine(sha+" refs/tags/v1.2.0\n") +
+				pktLine(sha+" refs/tags/v1.2.0^{}\n") + "0000",
+			want: []string{"v1.10.0", "v1.2.0"},
+		},
+		{
+			name:        "dumb server",
+			contentType: "text/plain",
+			body:        sha + "\trefs/tags/v1.0.0\n",
+			wantErr:     "does not speak the git smart HTTP protocol",
+		},
+		{
+			name:        "corrupt pkt-line",
+			contentType: "application/x-git-upload-pack-advertisement",
+			body:        pktLine("# service=git-upload-pack\n") + "0000" + "zz41" + sha,
+			wantErr:     "invalid pkt-line header",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/repo.git/info/refs" || r.URL.Query().Get("service") != "git-upload-pack" {
+					http.NotFound(w, r)
+					return
+				}
+				w.Header().Set("Content-Type", test.contentType)
+				io.WriteString(w, test.body)
+			}))
+			defer server.Close()
+
+			tags, err := listHTTPTags(server.URL + "/repo.git")
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Fatalf("got error %v, want %q", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Join(tags, ",") != strings.Join(test.want, ",") {
+				t.Errorf("got %v, want %v", tags, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// graphQLBatchSize is the number of repositories fetched per GraphQL query
+const graphQLBatchSize = 50
+
+// repoMetadata is everything the audits need about a GitHub repository, fetched in one GraphQL round trip
+type repoMetadata struct {
+	Archived      bool
+	LatestRelease *Release // nil when the repository has no published release
+	Releases      []Release
+	Tags          []string
+}
+
+type graphQLRelease struct {
+	TagName     string    `json:"tagName"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	PublishedAt time.Time `json:"publishedAt"`
+	IsDraft     bool      `json:"isDraft"`
+}
+
+type graphQLRepository struct {
+	IsArchived    bool            `json:"isArchived"`
+	LatestRelease *graphQLRelease `json:"latestRelease"`
+	Releases      struct {
+		Nodes []graphQLRelease `json:"nodes"`
+	} `json:"releases"`
+	Refs struct {
+		Nodes []struct {
+			Name string `json:"name"`
+		} `json:"nodes"`
+	} `json:"refs"`
+}
+
+type graphQLResponse struct {
+	Data   map[string]*graphQLRepository `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+const repositoryFields = `isArchived
+    latestRelease { tagName name description isDraft publishedAt }
+    releases(first: 30, orderBy: {field: CREATED_AT, direction: DESC}) { nodes { tagName name description isDraft publishedAt } }
+    refs(refPrefix: "refs/tags/", first: 30, orderBy: {field: TAG_COMMIT_DATE, direction: DESC}) { nodes { name } }`
+
+func metadataKey(repo Repository) string {
+	return strings.ToLower(repo.Path())
+}
+
+// Prefetch: Loads archived state, latest release, recent releases and tags for many repositories
+// through GitHub GraphQL, one query per 50 repositories instead of 2-3 REST calls each.
+// Repositories that fail to load are simply left to the REST fallback.
+func (g *GitHub) Prefetch(repos []Repository) error {
+	if g.client == nil {
+		return nil
+	}
+	if g.metadata == nil {
+		g.metadata = make(map[string]*repoMetadata)
+	}
+
+	var pending []Repository
+	seen := make(map[string]bool)
+	for _, repo := range repos {
+		key := metadataKey(repo)
+		if _, cached := g.metadata[key]; cached || seen[key] {
+			continue
+		}
+		seen[key] = true
+		pending = append(pending, repo)
+	}
+
+	for start := 0; start < len(pending); start += graphQLBatchSize {
+		end := start + graphQLBatchSize
+		if end > len(pending) {
+			end = len(pending)
+		}
+		if err := g.fetchBatch(pending[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fetchBatch: Runs one aliased query (r0, r1, ...) for up to graphQLBatchSize repositories
+func (g *GitHub) fetchBatch(batch []Repository) error {
+	var declarations, selections []string
+	variables := make(map[string]interface{})
+	for i, repo := range batch {
+		declarations = append(declarations, fmt.Sprintf("$o%d: String!, $n%d: String!", i, i))
+		selections = append(selections, fmt.Sprintf("  r%d: repository(owner: $o%d, name: $n%d) {\n    %s\n  }", i, i, i, repositoryFields))
+		variables[fmt.Sprintf("o%d", i)] = repo.Owner
+		variables[fmt.Sprintf("n%d", i)] = repo.Name
+	}
+	query := fmt.Sprintf("query(%s) {\n%s\n}", strings.Join(declarations, ", "), strings.Join(selections, "\n"))
+
+	// github.com serves GraphQL at /graphql, Enterprise Server at /api/graphql next to /api/v3/
+	endpoint := "graphql"
+	if g.client.BaseURL.Host != "api.github.com" {
+		endpoint = "../graphql"
+	}
+	req, err := g.client.NewRequest("POST", endpoint, map[string]interface{}{"query": query, "variables": variables})
+	if err != nil {
+		return err
+	}
+
+	var response graphQLResponse
+	if _, err := g.client.Do(context.Background(), req, &response); err != nil {
+		return g.rateLimit(err)
+	}
+	if len(response.Data) == 0 && len(response.Errors) > 0 {
+		return fmt.Errorf("GitHub GraphQL error: %s", response.Errors[0].Message)
+	}
+
+	// Missing or inaccessible repositories come back as null with a NOT_FOUND error; they are not cached
+	for i, repo := range batch {
+		node := response.Data[fmt.Sprintf("r%d", i)]
+		if node == nil {
+			continue
+		}
+
+		metadata := &repoMetadata{Archived: node.IsArchived}
+		if node.LatestRelease != nil {
+			metadata.LatestRelease = &Release{TagName: node.LatestRelease.TagName, Name: node.LatestRelease.Name, Body: node.LatestRelease.Description, PublishedAt: node.LatestRelease.PublishedAt}
+		}
+		for _, release := range node.Releases.Nodes {
+			if release.IsDraft {
+				continue
+			}
+			metadata.Releases = append(metadata.Releases, Release{TagName: release.TagName, Name: release.Name, Body: release.Description, PublishedAt: release.PublishedAt})
+		}
+		for _, tag := range node.Refs.Nodes {
+			metadata.Tags = append(metadata.Tags, tag.Name)
+		}
+		g.metadata[metadataKey(repo)] = metadata
+	}
+	return nil
+}
+
+// cached returns the prefetched metadata for repo, if any
+func (g *GitHub) cached(repo Repository) (*repoMetadata, bool) {
+	metadata, ok := g.metadata[metadataKey(repo)]
+	return metadata, ok
+}
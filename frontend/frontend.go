@@ -1,32 +1,40 @@
-package main
+// Package frontend audits the npm dependencies declared in a package.json.
+package frontend
 
 import (
 	"bufio"
-	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
-	"strconv"
 	"strings"
 	"time"
 
-	"github.com/google/go-github/v62/github"
-	"golang.org/x/mod/semver"
-	"golang.org/x/oauth2"
+	"Sbom/audit"
+	"Sbom/changelog"
+	"Sbom/forge"
+	"Sbom/version"
 )
 
 // --- Data Structures ---
 
 type UpdateInfo struct {
-	Repo             string
-	CurrentVersion   string
-	LatestVersion    string
-	UpdateNeeded     bool
-	SecurityPatch    bool
-	IsArchived       bool // NEW: To track if the repository is archived (deprecated)
-	ReleaseNotesList []string
-	Status           string
+	Repo            string
+	CurrentVersion  string
+	LatestVersion   string
+	UpdateNeeded    bool
+	SecurityPatch   bool
+	IsArchived      bool   // NEW: To track if the repository is archived (deprecated)
+	LinkURL         string // Release page of the latest changelog (or the tag list) on the package's forge
+	Bump            version.Bump
+	ReleasesBehind  int             // Releases between the current and the latest version, from the forge's release list
+	Changelog       changelog.Notes // Every release the update skips over, newest first
+	BreakingChanges []changelog.BreakingItem
+	NotesErr        *NotesError // Why Changelog is empty although an update is available
+	Advisories      []string    // Skipped releases whose notes mention a security fix
+	Error           string      // Why the package could not be checked
+	Status          string
 }
 
 type NpmPackageJSON struct {
@@ -46,18 +54,6 @@ type NpmInfo struct {
 
 // --- Utility Functions ---
 
-func createGitHubClient() *github.Client {
-	ctx := context.Background()
-	token := os.Getenv("GITHUB_TOKEN")
-	if token == "" {
-		// Fallback for unauthenticated client
-		return github.NewClient(nil)
-	}
-	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
-	tc := oauth2.NewClient(ctx, ts)
-	return github.NewClient(tc)
-}
-
 func parsePackageJSON(filename string) (NpmPackageJSON, error) {
 	var pkgJSON NpmPackageJSON
 	data, err := os.ReadFile(filename)
@@ -71,41 +67,6 @@ func parsePackageJSON(filename string) (NpmPackageJSON, error) {
 	return pkgJSON, nil
 }
 
-func parseGitHubRepoURL(url string) (owner, repo string) {
-	// Aggressive cleaning logic (Unchanged)
-	url = strings.TrimPrefix(url, "git://")
-	url = strings.TrimPrefix(url, "git+https://")
-	url = strings.TrimPrefix(url, "https://")
-	url = strings.TrimPrefix(url, "http://")
-	url = strings.TrimPrefix(url, "git@")
-
-	url = strings.Split(url, "#")[0]
-	url = strings.TrimSuffix(url, ".git")
-
-	if parts := strings.Split(url, ":"); len(parts) > 1 {
-		url = parts[1]
-	}
-
-	parts := strings.Split(url, "/")
-	var filteredParts []string
-	for _, p := range parts {
-		if p != "" {
-			filteredParts = append(filteredParts, p)
-		}
-	}
-	parts = filteredParts
-
-	if len(parts) >= 2 && (strings.Contains(parts[0], "github.com") || strings.Contains(parts[0], "gitlab.com")) {
-		if len(parts) >= 3 {
-			return parts[1], parts[2]
-		}
-	} else if len(parts) >= 2 {
-		return parts[0], parts[1]
-	}
-
-	return "", ""
-}
-
 func fetchNpmInfo(pkgName string) (*NpmInfo, error) {
 	url := fmt.Sprintf("https://registry.npmjs.org/%s/latest", pkgName)
 	resp, err := http.Get(url)
@@ -125,67 +86,45 @@ func fetchNpmInfo(pkgName string) (*NpmInfo, error) {
 	return &info, nil
 }
 
-// --- Changelog & Error Extraction Helpers (Unchanged) ---
+// --- Release Notes ---
 
-func getOwnerRepoFromChangelog(notes string) (owner, repo string) {
-	if ownerIndex := strings.Index(notes, "(Owner: "); ownerIndex != -1 {
-		ownerEnd := strings.Index(notes[ownerIndex+8:], ")")
-		if ownerEnd != -1 {
-			owner = notes[ownerIndex+8 : ownerIndex+8+ownerEnd]
-		}
-	}
-	if repoIndex := strings.Index(notes, "(Repo: "); repoIndex != -1 {
-		repoEnd := strings.Index(notes[repoIndex+7:], ")")
-		if repoEnd != -1 {
-			repo = notes[repoIndex+7 : repoIndex+7+repoEnd]
-		}
-	}
-	return
+// NotesError explains why no release notes are shown for an outdated package
+type NotesError struct {
+	Forge string
+	Repo  string    // "owner/name" on the forge
+	Reset time.Time // Set when the forge's rate limit was exceeded
+	Err   error     // nil when the forge answered, but had no notes for the versions in between
 }
 
-func getOwnerRepoFromError(notes string) (owner, repo string) {
-	if strings.Contains(notes, "GitHub (") {
-		start := strings.Index(notes, "GitHub (") + 8
-		end := strings.Index(notes, ")")
-		if end > start {
-			parts := strings.Split(notes[start:end], "/")
-			if len(parts) >= 2 {
-				repoWithSuffix := strings.Split(parts[1], ".git")[0]
-				return parts[0], repoWithSuffix
-			}
-		}
+func (e *NotesError) Error() string {
+	switch {
+	case !e.Reset.IsZero():
+		return fmt.Sprintf("%s Rate Limit Exceeded. Try again after %s.", e.Forge, e.Reset.Format(time.RFC1123))
+	case e.Err != nil:
+		return fmt.Sprintf("Could not list releases from %s (%s). Error: %v", e.Forge, e.Repo, e.Err)
 	}
-	return "", ""
+	return fmt.Sprintf("Could not fetch specific release details from %s (%s), or only tags exist.", e.Forge, e.Repo)
 }
 
-func extractBodyFromChangelog(notes string) string {
-	body := strings.Split(notes, "---")[2]
-	body = strings.TrimSpace(body)
-
-	body = strings.ReplaceAll(body, "*", "")
-	body = strings.ReplaceAll(body, "**", "")
-	body = strings.ReplaceAll(body, "#", "")
-	body = strings.ReplaceAll(body, "[", "")
-	body = strings.ReplaceAll(body, "]", "")
-	body = strings.ReplaceAll(body, "(", "")
-	body = strings.ReplaceAll(body, ")", "")
-	body = strings.ReplaceAll(body, "`", "")
+func (e *NotesError) Unwrap() error {
+	return e.Err
+}
 
-	body = strings.ReplaceAll(body, "\n", " ")
-	body = strings.ReplaceAll(body, "\r", " ")
+// summarizeBody: Flattens a Markdown release body into one table-safe line of at most limit characters
+func summarizeBody(body string, limit int) string {
+	body = strings.NewReplacer("*", "", "#", "", "[", "", "]", "", "(", "", ")", "", "`", "", "\n", " ", "\r", " ").Replace(body)
+	body = strings.Join(strings.Fields(body), " ")
 
-	for strings.Contains(body, "  ") {
-		body = strings.ReplaceAll(body, "  ", " ")
+	if runes := []rune(body); len(runes) > limit {
+		body = strings.TrimSpace(string(runes[:limit])) + "..."
 	}
-
-	body = strings.ReplaceAll(body, "|", "\\|")
-
-	return body
+	return strings.ReplaceAll(body, "|", "\\|")
 }
 
 // --- Core Check Logic (Updated to include Archival Check) ---
 
-func checkNpmUpdate(client *github.Client, pkgName, currentVer string) UpdateInfo {
+// checkNpmUpdate: npmInfo may be pre-fetched by the caller; nil fetches it from the registry
+func checkNpmUpdate(router *forge.Router, pkgName, currentVer string, npmInfo *NpmInfo) UpdateInfo {
 	cleanVer := strings.TrimFunc(currentVer, func(r rune) bool {
 		return strings.ContainsRune("^~=>", r)
 	})
@@ -199,10 +138,14 @@ func checkNpmUpdate(client *github.Client, pkgName, currentVer string) UpdateInf
 		LatestVersion:  "N/A",
 	}
 
-	npmInfo, err := fetchNpmInfo(pkgName)
-	if err != nil {
-		info.Status = "❌ NPM Fetch Error: " + err.Error()
-		return info
+	if npmInfo == nil {
+		var err error
+		npmInfo, err = fetchNpmInfo(pkgName)
+		if err != nil {
+			info.Error = err.Error()
+			info.Status = "❌ NPM Fetch Error: " + info.Error
+			return info
+		}
 	}
 
 	latestVer := npmInfo.Version
@@ -211,25 +154,26 @@ func checkNpmUpdate(client *github.Client, pkgName, currentVer string) UpdateInf
 	}
 	info.LatestVersion = latestVer
 
-	if semver.Compare(info.CurrentVersion, info.LatestVersion) >= 0 {
+	scheme := version.For("npm", pkgName)
+	if scheme.Compare(info.CurrentVersion, info.LatestVersion) >= 0 {
 		info.UpdateNeeded = false // Explicitly set to false if up-to-date
 	} else {
 		info.UpdateNeeded = true
+		info.Bump = scheme.Classify(info.CurrentVersion, info.LatestVersion)
 	}
 
-	repoURL := npmInfo.Repository.URL
-	owner, repo := parseGitHubRepoURL(repoURL)
-
-	if owner == "" || repo == "" {
+	source, repository, err := router.Resolve(npmInfo.Repository.URL)
+	if err != nil {
 		info.Status = "🔄 Update Recommended (Repo link missing)"
 		return info
 	}
+	owner, repo := repository.Owner, repository.Name
 
 	// --- 1. CHECK ARCHIVED (DEPRECATED) STATUS ---
-	repoDetails, _, repoErr := client.Repositories.Get(context.Background(), owner, repo)
+	archived, repoErr := source.IsArchived(repository)
 	if repoErr != nil {
 		fmt.Printf(" [ERROR] Could not fetch repo details for %s/%s: %v\n", owner, repo, repoErr)
-	} else if repoDetails.GetArchived() {
+	} else if archived {
 		info.IsArchived = true
 		info.Status = "⛔️ DEPRECATED (Archived)"
 		fmt.Printf(" [DEPRECATED] Repository %s/%s is ARCHIVED.\n", owner, repo)
@@ -244,52 +188,40 @@ func checkNpmUpdate(client *github.Client, pkgName, currentVer string) UpdateInf
 	// --- 2. VERSION & SECURITY CHECK (Only if UpdateNeeded) ---
 	if info.UpdateNeeded {
 
-		releases, resp, listErr := client.Repositories.ListReleases(context.Background(), owner, repo, &github.ListOptions{
-			PerPage: 30,
-		})
+		notes, listErr := changelog.Collect(source, repository, scheme, info.CurrentVersion, info.LatestVersion)
 
-		if resp != nil && resp.StatusCode == http.StatusForbidden && strings.Contains(resp.Header.Get("X-RateLimit-Remaining"), "0") {
-			resetTimeString := resp.Header.Get("X-RateLimit-Reset")
-			resetTimeInt, _ := strconv.ParseInt(resetTimeString, 10, 64)
-			info.ReleaseNotesList = append(info.ReleaseNotesList, fmt.Sprintf("❌ GitHub Rate Limit Exceeded. Try again after %s. (Repo: %s/%s)", time.Unix(resetTimeInt, 0).Format(time.RFC1123), owner, repo))
+		var rateErr *forge.RateLimitError
+		if errors.As(listErr, &rateErr) {
+			info.NotesErr = &NotesError{Forge: rateErr.Forge, Repo: repository.Path(), Reset: rateErr.Reset, Err: listErr}
 
 		} else if listErr != nil {
-			info.ReleaseNotesList = append(info.ReleaseNotesList, fmt.Sprintf("Warning: Could not list releases from GitHub (%s/%s). Error: %v", owner, repo, listErr))
+			info.NotesErr = &NotesError{Forge: source.Name(), Repo: repository.Path(), Err: listErr}
+			info.LinkURL = source.TagsURL(repository)
 
 		} else {
-			foundLatestReleaseChangelog := false
-
-			for _, release := range releases {
-				tag := release.GetTagName()
-
-				cleanTagParts := strings.Split(tag, "@")
-				if len(cleanTagParts) > 1 {
-					tag = cleanTagParts[len(cleanTagParts)-1]
-				}
-				if !strings.HasPrefix(tag, "v") {
-					tag = "v" + tag
-				}
+			var newerTags []string
 
-				if semver.Compare(tag, info.CurrentVersion) <= 0 {
-					break
-				}
+			// Security Check (Checks all intermediate versions)
+			for _, release := range notes.Releases {
+				newerTags = append(newerTags, changelog.TagVersion(release.Tag))
 
-				// Security Check (Checks all intermediate versions)
-				body := strings.ToLower(release.GetBody() + " " + release.GetName())
+				body := strings.ToLower(release.Body + " " + release.Name)
 				if strings.Contains(body, "security") || strings.Contains(body, "vulnerability") || strings.Contains(body, "cve") || strings.Contains(body, "patch") {
 					info.SecurityPatch = true
-				}
-
-				// Store Changelog for the very latest version only
-				if !foundLatestReleaseChangelog {
-					releaseDetail := fmt.Sprintf("\n--- Latest Changelog for %s (Tag: %s) (Owner: %s) (Repo: %s) ---\n%s\n", release.GetName(), release.GetTagName(), owner, repo, release.GetBody())
-					info.ReleaseNotesList = append(info.ReleaseNotesList, releaseDetail)
-					foundLatestReleaseChangelog = true
+					info.Advisories = append(info.Advisories, "Security fix in "+release.Tag)
 				}
 			}
 
-			if !foundLatestReleaseChangelog {
-				info.ReleaseNotesList = append(info.ReleaseNotesList, fmt.Sprintf("Warning: Could not fetch specific release details for version %s, or only tags exist. (Repo: %s/%s)", info.LatestVersion, owner, repo))
+			info.ReleasesBehind = scheme.Behind(info.CurrentVersion, info.LatestVersion, newerTags)
+			info.Changelog = notes
+			info.BreakingChanges = changelog.Breaking(notes)
+
+			if len(notes.Releases) > 0 {
+				// The summary links the newest release; the report lists all of them
+				info.LinkURL = notes.Releases[0].URL
+			} else {
+				info.NotesErr = &NotesError{Forge: source.Name(), Repo: repository.Path()}
+				info.LinkURL = source.TagsURL(repository)
 			}
 		}
 
@@ -306,10 +238,10 @@ func checkNpmUpdate(client *github.Client, pkgName, currentVer string) UpdateInf
 		info.Status = "🚨 URGENT Update Required (Security Patch!)"
 	} else if !info.UpdateNeeded {
 		info.Status = "✅ Up to date"
-	} else if len(info.ReleaseNotesList) == 0 || strings.HasPrefix(info.ReleaseNotesList[0], "Warning:") || strings.HasPrefix(info.ReleaseNotesList[0], "❌") {
-		info.Status = "🔄 Update Recommended (Changelog unavailable)"
+	} else if info.NotesErr != nil || len(info.Changelog.Releases) == 0 {
+		info.Status = audit.UpdateStatus(info.Bump) + " (Changelog unavailable)"
 	} else {
-		info.Status = "🔄 Update Recommended"
+		info.Status = audit.UpdateStatus(info.Bump)
 	}
 
 	return info
@@ -317,107 +249,114 @@ func checkNpmUpdate(client *github.Client, pkgName, currentVer string) UpdateInf
 
 // --- Output Function (Markdown Table) ---
 
-func writeOutput(pkgJSON NpmPackageJSON, infos []UpdateInfo, filename string) error {
+// reportTemplate is the default package.json report: project header, package table, then the upgrade changelogs
+var reportTemplate = audit.Template{
+	Name: "frontend",
+	Text: `# 📈 Frontend Dependency Update Report
+
+## Project: **{{.Results.Project.Name}}** ({{code .Results.Project.Version}})
+This report summarizes the update status for your main dependencies ({{code "dependencies"}}).
+> **Note:** Patch and minor updates should be safe to apply; major updates (including 0.x minor bumps) are likely to contain breaking changes. Security patches are flagged as URGENT.
+
+---
+
+## Summary of Update Status
+
+{{summary .Report}}{{table .Results.Packages .Report.Findings}}{{changelogs .Results.Packages}}`,
+	Funcs: map[string]interface{}{
+		"table": func(infos []UpdateInfo, findings []audit.Finding) string {
+			return audit.Capture(func(writer *bufio.Writer) { writeSummaryTable(writer, infos, findings) })
+		},
+		"changelogs": func(infos []UpdateInfo) string {
+			return audit.Capture(func(writer *bufio.Writer) { writeUpgradeChangelogs(writer, infos) })
+		},
+	},
+}
+
+// ProjectReport is the model of the frontend report: the package.json and its checked dependencies
+type ProjectReport struct {
+	Project  NpmPackageJSON
+	Packages []UpdateInfo
+}
+
+// writeOutput: Renders the results through reportTemplate
+func writeOutput(pkgJSON NpmPackageJSON, infos []UpdateInfo, report *audit.Report, filename string) error {
 	if !strings.HasSuffix(filename, ".md") {
 		filename += ".md"
 	}
+	data := audit.TemplateData{Report: report, Results: ProjectReport{Project: pkgJSON, Packages: infos}}
+	return reportTemplate.Write(filename, data)
+}
 
-	file, err := os.Create(filename)
-	if err != nil {
-		return fmt.Errorf("error creating output file: %w", err)
+// writeUpgradeChangelogs lists, per outdated package, the notes of every release between the current and the latest version
+func writeUpgradeChangelogs(writer *bufio.Writer, infos []UpdateInfo) {
+	wroteHeader := false
+	for _, info := range infos {
+		if !info.UpdateNeeded || len(info.Changelog.Releases) == 0 {
+			continue
+		}
+		if !wroteHeader {
+			_, _ = writer.WriteString("\n---\n\n## 📝 Upgrade Changelogs\n\n")
+			_, _ = writer.WriteString("> Every release between the current and the latest version, ordered from newest to oldest.\n\n")
+			wroteHeader = true
+		}
+		_, _ = writer.WriteString(fmt.Sprintf("### `%s` (`%s` → `%s`)\n\n", info.Repo, info.CurrentVersion, info.LatestVersion))
+		if len(info.BreakingChanges) > 0 {
+			_, _ = writer.WriteString("**Upgrade notes:**\n\n")
+			_, _ = writer.WriteString(changelog.FormatBreaking(info.BreakingChanges) + "\n")
+		}
+		_, _ = writer.WriteString(changelog.Consolidate(info.Changelog))
 	}
-	defer file.Close()
-
-	writer := bufio.NewWriter(file)
-	defer writer.Flush()
-
-	// 1. Project Info Header
-	_, _ = writer.WriteString(fmt.Sprintf("# 📈 Frontend Dependency Update Report\n\n"))
-	_, _ = writer.WriteString(fmt.Sprintf("## Project: **%s** (`%s`)\n", pkgJSON.Name, pkgJSON.Version))
-	_, _ = writer.WriteString("This report summarizes the update status for your main dependencies (`dependencies`).\n")
-	_, _ = writer.WriteString("> **Note:** 'Update Recommended' means updating is advised, unless a security patch is explicitly noted.\n\n")
-	_, _ = writer.WriteString("---\n\n")
-
-	_, _ = writer.WriteString("## Summary of Update Status\n\n")
+}
 
+// writeSummaryTable writes the per-package Markdown table (shared with combined scan reports) from
+// the report's findings; infos, in the same order, add the links and changelog summaries
+func writeSummaryTable(writer *bufio.Writer, infos []UpdateInfo, findings []audit.Finding) {
 	// Markdown Table Header
-	_, _ = writer.WriteString("| # | 📦 Package | 🟢 Status | 🏷️ Current Version | ⬆️ Latest Version | 📝 Changelog Summary |\n")
-	_, _ = writer.WriteString("| :---: | :--- | :---: | :---: | :---: | :--- |\n")
+	_, _ = writer.WriteString("| # | 📦 Package | 🟢 Status | 📐 Change | 🏷️ Current Version | ⬆️ Latest Version | 📝 Changelog Summary |\n")
+	_, _ = writer.WriteString("| :---: | :--- | :---: | :---: | :---: | :---: | :--- |\n")
 
-	index := 1
-	for _, info := range infos {
-		// 1. Determine Status Display
-		statusDisplay := info.Status
+	for i, finding := range findings {
+		info := infos[i]
+		// 1. Determine Status Display, with the issues the baseline accepts
+		statusDisplay := audit.AcceptedStatus(finding.Status, finding)
 
 		// 2. Extract Link and Changelog Summary
-		repoLinkURL := ""
+		repoLinkURL := info.LinkURL
 		changelogSummary := "N/A"
 
-		if len(info.ReleaseNotesList) > 0 {
-			notes := info.ReleaseNotesList[0]
-
-			if strings.HasPrefix(notes, "❌") || strings.HasPrefix(notes, "Warning:") {
-				if owner, repo := getOwnerRepoFromError(notes); owner != "" {
-					repoLinkURL = fmt.Sprintf("https://github.com/%s/%s/tags", owner, repo)
-					changelogSummary = strings.Split(notes, "(Repo:")[0]
-					changelogSummary = strings.TrimPrefix(changelogSummary, "Warning: ")
-					changelogSummary = strings.TrimPrefix(changelogSummary, "❌ ")
-				} else {
-					changelogSummary = "❌ Error: GitHub access"
-				}
-			} else {
-				owner, repo := getOwnerRepoFromChangelog(notes)
-				tagStart := strings.Index(notes, "(Tag:") + 6
-				tagEnd := strings.Index(notes, ")")
-				gitHubTag := ""
-				if tagEnd > tagStart {
-					gitHubTag = strings.TrimSpace(notes[tagStart:tagEnd])
-				}
-
-				if owner != "" {
-					if gitHubTag != "" {
-						repoLinkURL = fmt.Sprintf("https://github.com/%s/%s/releases/tag/%s", owner, repo, gitHubTag)
-					} else {
-						repoLinkURL = fmt.Sprintf("https://github.com/%s/%s", owner, repo)
-					}
-				}
-
-				changelogBody := extractBodyFromChangelog(notes)
-				if len(changelogBody) > 80 {
-					changelogSummary = strings.TrimSpace(changelogBody[:80]) + "..."
-				} else {
-					changelogSummary = strings.TrimSpace(changelogBody)
-				}
+		if info.NotesErr != nil {
+			switch {
+			case !info.NotesErr.Reset.IsZero():
+				changelogSummary = "❌ " + info.NotesErr.Error()
+			case repoLinkURL != "":
+				changelogSummary = strings.ReplaceAll(info.NotesErr.Error(), "|", "\\|")
+			default:
+				changelogSummary = "❌ Error: forge access"
 			}
+		} else if len(info.Changelog.Releases) > 0 {
+			changelogSummary = summarizeBody(info.Changelog.Releases[0].Body, 80)
 		}
 
 		// 3. Create Markdown link for Latest Version
-		latestVersionDisplay := info.LatestVersion
+		latestVersionDisplay := finding.LatestVersion
 		if repoLinkURL != "" {
-			latestVersionDisplay = fmt.Sprintf("[`%s`](%s)", info.LatestVersion, repoLinkURL)
+			latestVersionDisplay = fmt.Sprintf("[`%s`](%s)", finding.LatestVersion, repoLinkURL)
 		}
 
 		// 4. Write table row
-		line := fmt.Sprintf("| %d | `%s` | %s | `%s` | %s | %s |\n",
-			index, info.Repo, statusDisplay, info.CurrentVersion, latestVersionDisplay, changelogSummary)
+		line := fmt.Sprintf("| %d | `%s` | %s | %s | `%s` | %s | %s |\n",
+			i+1, finding.Name, statusDisplay, audit.ChangeLabel(finding.Bump, finding.ReleasesBehind), finding.CurrentVersion, latestVersionDisplay, changelogSummary)
 		_, _ = writer.WriteString(line)
-		index++
 	}
-
-	return nil
 }
 
-func main() {
-	const packageFileName = "frontend/package.json"
-	const outputFile = "frontend/report.md"
-
-	client := createGitHubClient()
-
+// auditPackageJSON reads a package.json and checks every registry dependency
+func auditPackageJSON(router *forge.Router, packageFileName string) (NpmPackageJSON, []UpdateInfo, error) {
 	// 1. Read from file
 	pkgJSON, err := parsePackageJSON(packageFileName)
 	if err != nil {
-		fmt.Printf("Fatal Error: Could not read or parse %s. %v\n", packageFileName, err)
-		return
+		return pkgJSON, nil, err
 	}
 
 	var results []UpdateInfo
@@ -434,18 +373,94 @@ func main() {
 
 	fmt.Printf("Starting check for %d packages (Project: %s@%s)...\n", len(filteredPackages), pkgJSON.Name, pkgJSON.Version)
 
+	// Resolve every package's repository first, so forge metadata can be fetched in bulk
+	npmInfos := make(map[string]*NpmInfo)
+	var repositories []forge.Repository
+	for pkgName := range filteredPackages {
+		npmInfo, err := fetchNpmInfo(pkgName)
+		if err != nil {
+			continue // Reported by checkNpmUpdate, which retries the fetch
+		}
+		npmInfos[pkgName] = npmInfo
+		if repository, ok := forge.ParseRepoURL(npmInfo.Repository.URL); ok {
+			repositories = append(repositories, repository)
+		}
+	}
+	router.Prefetch(repositories)
+
 	for pkgName, currentVer := range filteredPackages {
 
 		fmt.Printf("-> Checking NPM package %s (Current: %s)...\n", pkgName, currentVer)
-		info := checkNpmUpdate(client, pkgName, currentVer)
+		info := checkNpmUpdate(router, pkgName, currentVer, npmInfos[pkgName])
 		results = append(results, info)
 	}
 
-	err = writeOutput(pkgJSON, results, outputFile)
+	return pkgJSON, results, nil
+}
+
+// ScanManifest audits one package.json for combined scan reports; its summary table is written
+// once the findings are added to the report
+func ScanManifest(router *forge.Router, packageFileName string) ([]audit.Finding, audit.TableWriter, error) {
+	_, results, err := auditPackageJSON(router, packageFileName)
+	if err != nil {
+		return nil, nil, err
+	}
+	table := func(writer *bufio.Writer, findings []audit.Finding) { writeSummaryTable(writer, results, findings) }
+	return toFindings(results), table, nil
+}
+
+// toFindings converts the audited packages into the ecosystem-neutral model
+func toFindings(results []UpdateInfo) []audit.Finding {
+	var findings []audit.Finding
+	for _, info := range results {
+		findings = append(findings, audit.Finding{
+			Name:            info.Repo,
+			CurrentVersion:  info.CurrentVersion,
+			LatestVersion:   info.LatestVersion,
+			Status:          info.Status,
+			Compared:        info.Error == "", // The registry's latest version is known unless fetching it failed
+			Error:           info.Error,
+			UpdateNeeded:    info.UpdateNeeded,
+			Vulnerable:      info.SecurityPatch,
+			Archived:        info.IsArchived,
+			Bump:            info.Bump,
+			ReleasesBehind:  info.ReleasesBehind,
+			Advisories:      info.Advisories,
+			Changelog:       info.Changelog.Releases,
+			BreakingChanges: info.BreakingChanges,
+		})
+	}
+	return findings
+}
+
+// Run audits frontend/package.json (or the package.json given as first argument)
+func Run(args []string) {
+	packageFileName := "frontend/package.json"
+	if len(args) > 0 {
+		packageFileName = args[0]
+	}
+	const outputFile = "frontend/report.md"
+
+	router := forge.NewRouter(forge.NewGitHubClient())
+
+	report := audit.NewReport("frontend", packageFileName)
+	started := time.Now()
+	pkgJSON, results, err := auditPackageJSON(router, packageFileName)
+	if err != nil {
+		fmt.Printf("Fatal Error: Could not read or parse %s. %v\n", packageFileName, err)
+		return
+	}
+	report.Add(audit.NewSource(packageFileName, "npm", started, toFindings(results), nil))
+	report.Finish()
+
+	err = writeOutput(pkgJSON, results, report, outputFile)
+	if err == nil {
+		err = report.WriteFiles(outputFile)
+	}
 	if err != nil {
 		fmt.Printf("Fatal Error writing output: %v\n", err)
 		return
 	}
 
-	fmt.Printf("✅ Operation completed successfully. Results saved in **%s**.\n", outputFile)
+	fmt.Printf("✅ Operation completed successfully. Results saved in %s.\n", audit.OutputFiles(reportTemplate.Name, outputFile))
 }
@@ -3,8 +3,9 @@ module Sbom
 go 1.25
 
 require (
-	github.com/google/go-github/v62 v62.0.0 // indirect
-	github.com/google/go-querystring v1.1.0 // indirect
-	golang.org/x/mod v0.29.0 // indirect
-	golang.org/x/oauth2 v0.33.0 // indirect
+	github.com/google/go-github/v62 v62.0.0
+	golang.org/x/mod v0.29.0
+	golang.org/x/oauth2 v0.33.0
 )
+
+require github.com/google/go-querystring v1.1.0 // indirect
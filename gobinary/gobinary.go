@@ -0,0 +1,433 @@
+// Package gobinary audits the modules of compiled Go binaries and go.mod files against the module proxy.
+package gobinary
+
+import (
+	"bufio"
+	"debug/buildinfo"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"Sbom/audit"
+	"Sbom/forge"
+	"Sbom/version"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// --- Data Structures ---
+
+type ModuleInfo struct {
+	Path           string
+	CurrentVersion string
+	Replacement    string // "path version" of a replace directive, if any
+	Main           bool
+	LatestVersion  string
+	UpdateNeeded   bool
+	SecurityPatch  bool
+	Bump           version.Bump
+	ReleasesBehind int    // Tagged versions between the current and the latest version
+	Error          string // Why the module could not be checked
+	Status         string
+}
+
+type BinaryReport struct {
+	File      string
+	GoVersion string
+	Settings  []debug.BuildSetting
+	Modules   []ModuleInfo
+}
+
+// ProxyInfo is the response of the module proxy's @latest endpoint
+type ProxyInfo struct {
+	Version string `json:"Version"`
+	Time    string `json:"Time"`
+}
+
+// --- Build Info Extraction ---
+
+// readBinary: Reads the module information the Go linker embeds in every executable
+func readBinary(filename string) (BinaryReport, error) {
+	report := BinaryReport{File: filename}
+	info, err := buildinfo.ReadFile(filename)
+	if err != nil {
+		return report, fmt.Errorf("error reading build info from %s: %w", filename, err)
+	}
+
+	report.GoVersion = info.GoVersion
+	report.Settings = info.Settings
+	report.Modules = append(report.Modules, ModuleInfo{
+		Path:           info.Main.Path,
+		CurrentVersion: info.Main.Version,
+		Main:           true,
+	})
+	for _, dep := range info.Deps {
+		mod := ModuleInfo{Path: dep.Path, CurrentVersion: dep.Version}
+		if dep.Replace != nil {
+			mod.Replacement = strings.TrimSpace(dep.Replace.Path + " " + dep.Replace.Version)
+		}
+		report.Modules = append(report.Modules, mod)
+	}
+	return report, nil
+}
+
+// --- Module Proxy Client ---
+
+// moduleProxyURL: Picks the proxy the go command would ask for a module: none for modules matching
+// GONOPROXY (GOPRIVATE when unset), otherwise the first HTTP entry of GOPROXY, falling back to
+// proxy.golang.org. GOPROXY=off and direct also ask no proxy. Without a proxy, reason says why.
+func moduleProxyURL(modulePath string) (proxyURL, reason string) {
+	noProxy := os.Getenv("GONOPROXY")
+	if noProxy == "" {
+		noProxy = os.Getenv("GOPRIVATE")
+	}
+	if noProxy != "" && module.MatchPrefixPatterns(noProxy, modulePath) {
+		return "", "private module"
+	}
+
+	goproxy := os.Getenv("GOPROXY")
+	if goproxy == "" {
+		return "https://proxy.golang.org", ""
+	}
+	for _, entry := range strings.FieldsFunc(goproxy, func(r rune) bool { return r == ',' || r == '|' }) {
+		switch entry = strings.TrimSpace(entry); {
+		case strings.HasPrefix(entry, "http"):
+			return strings.TrimSuffix(entry, "/"), ""
+		case entry == "off" || entry == "direct":
+			return "", "GOPROXY=" + entry
+		}
+	}
+	return "", "no HTTP proxy in GOPROXY"
+}
+
+func fetchLatestModuleVersion(proxyURL, modulePath string) (string, error) {
+	escaped, err := module.EscapePath(modulePath)
+	if err != nil {
+		return "", err
+	}
+	resp, err := http.Get(fmt.Sprintf("%s/%s/@latest", proxyURL, escaped))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("module proxy returned status %d for %s", resp.StatusCode, modulePath)
+	}
+	var info ProxyInfo
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return "", err
+	}
+	return info.Version, nil
+}
+
+// fetchModuleVersions: Lists every tagged version of a module from the proxy's @v/list endpoint
+func fetchModuleVersions(proxyURL, modulePath string) ([]string, error) {
+	escaped, err := module.EscapePath(modulePath)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.Get(fmt.Sprintf("%s/%s/@v/list", proxyURL, escaped))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("module proxy returned status %d for %s", resp.StatusCode, modulePath)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(body)), nil
+}
+
+// repoForModule: Maps host/owner/repo[/v2/...] module paths (github.com, gitlab.com, ...) to their repository
+func repoForModule(modulePath string) (forge.Repository, bool) {
+	parts := strings.Split(modulePath, "/")
+	if len(parts) < 3 || !strings.Contains(parts[0], ".") {
+		return forge.Repository{}, false
+	}
+	return forge.Repository{Host: parts[0], Owner: parts[1], Name: parts[2]}, true
+}
+
+// hasSecurityRelease: Scans the release notes between current and latest for security keywords
+func hasSecurityRelease(source forge.Forge, repository forge.Repository, currentVer string) (bool, error) {
+	releases, err := source.ListReleases(repository)
+	if err != nil {
+		return false, err
+	}
+
+	for _, release := range releases {
+		tag := release.TagName
+		// Multi-module repositories tag as <subdir>/vX.Y.Z
+		tag = tag[strings.LastIndex(tag, "/")+1:]
+		if !semver.IsValid(tag) || semver.Compare(tag, currentVer) <= 0 {
+			continue
+		}
+		body := strings.ToLower(release.Body + " " + release.Name)
+		if strings.Contains(body, "security") || strings.Contains(body, "vulnerability") || strings.Contains(body, "cve") {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// --- Core Check Logic ---
+
+func checkModuleUpdate(router *forge.Router, mod ModuleInfo) ModuleInfo {
+	auditPath, currentVer := mod.Path, mod.CurrentVersion
+	if mod.Replacement != "" {
+		parts := strings.Fields(mod.Replacement)
+		if len(parts) < 2 {
+			mod.Status = "📁 Local replacement (not audited)"
+			return mod
+		}
+		auditPath, currentVer = parts[0], parts[1]
+	}
+
+	if currentVer == "" || currentVer == "(devel)" {
+		mod.Status = "⚪ Development build (no version)"
+		return mod
+	}
+
+	// Private modules are not looked up anywhere, so their paths never reach a public proxy or forge
+	proxyURL, reason := moduleProxyURL(auditPath)
+	if proxyURL == "" {
+		mod.Status = fmt.Sprintf("🔒 Not audited (%s)", reason)
+		return mod
+	}
+
+	latest, err := fetchLatestModuleVersion(proxyURL, auditPath)
+	if err != nil {
+		mod.Error = err.Error()
+		mod.Status = "❌ Error: " + mod.Error
+		return mod
+	}
+	mod.LatestVersion = latest
+
+	if semver.Compare(currentVer, latest) >= 0 {
+		mod.Status = "✅ Up to Date"
+		return mod
+	}
+	mod.UpdateNeeded = true
+	scheme := version.For("go", auditPath)
+	mod.Bump = scheme.Classify(currentVer, latest)
+	if versions, err := fetchModuleVersions(proxyURL, auditPath); err == nil {
+		mod.ReleasesBehind = scheme.Behind(currentVer, latest, versions)
+	}
+
+	// Modules on hosts without a supported forge (golang.org/x, gopkg.in, ...) are not scanned for security releases
+	if repository, ok := repoForModule(auditPath); ok {
+		if source, err := router.For(repository); err == nil {
+			security, err := hasSecurityRelease(source, repository, currentVer)
+			if err != nil {
+				fmt.Printf(" [ERROR] Could not list releases for %s: %v\n", repository.Path(), err)
+			}
+			mod.SecurityPatch = security
+		}
+	}
+
+	if mod.SecurityPatch {
+		mod.Status = "🚨 URGENT Update Required (Security Patch!)"
+	} else {
+		mod.Status = audit.UpdateStatus(mod.Bump)
+	}
+	return mod
+}
+
+// --- Output Function (Markdown) ---
+
+// reportTemplate writes one section per binary: its Go version, build settings and module table
+var reportTemplate = audit.Template{
+	Name: "gobinary",
+	Text: `## 🐹 Go Binary Module Audit
+
+This report lists the modules compiled into each Go executable and compares them against the module proxy.
+
+{{summary .Report}}{{range .Results}}### 📦 {{code .File}}
+
+* Go Version: {{code .GoVersion}}
+{{range .Settings}}{{if .Value}}* {{code .Key}}: {{code .Value}}
+{{end}}{{end}}
+{{table .Modules ($.Report.SourceFindings .File)}}
+---
+
+{{end}}`,
+	Funcs: map[string]interface{}{
+		"table": func(modules []ModuleInfo, findings []audit.Finding) string {
+			return audit.Capture(func(writer *bufio.Writer) { writeTable(writer, modules, findings) })
+		},
+	},
+}
+
+// printReport: Renders the binaries ([]BinaryReport) through reportTemplate
+func printReport(reports []BinaryReport, summary *audit.Report, filename string) error {
+	return reportTemplate.Write(filename, audit.TemplateData{Report: summary, Results: reports})
+}
+
+// writeTable writes the module table (shared with combined scan reports) from the report's findings;
+// modules, in the same order, add the main module and replacements
+func writeTable(writer *bufio.Writer, modules []ModuleInfo, findings []audit.Finding) {
+	writer.WriteString("| # | Module | Status | Change | Current Version | Latest Version | Replaced By |\n")
+	writer.WriteString("| :---: | :--- | :---: | :---: | :---: | :---: | :--- |\n")
+	for i, finding := range findings {
+		mod := modules[i]
+		statusDisplay := finding.Status
+		if finding.UpdateNeeded {
+			statusDisplay = "**" + statusDisplay + "**"
+		}
+		statusDisplay = audit.AcceptedStatus(statusDisplay, finding)
+		name := "`" + finding.Name + "`"
+		if mod.Main {
+			name += " (main)"
+		}
+		line := fmt.Sprintf("| %d | %s | %s | %s | `%s` | `%s` | %s |\n",
+			i+1, name, statusDisplay, audit.ChangeLabel(finding.Bump, finding.ReleasesBehind), finding.CurrentVersion, finding.LatestVersion, mod.Replacement)
+		writer.WriteString(line)
+	}
+}
+
+// --- go.mod Scanning ---
+
+// readGoMod: Lists the required modules of a go.mod, applying its replace directives
+func readGoMod(filename string) ([]ModuleInfo, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", filename, err)
+	}
+	file, err := modfile.Parse(filename, data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s: %w", filename, err)
+	}
+
+	replacements := make(map[string]string)
+	for _, replace := range file.Replace {
+		replacements[replace.Old.Path] = strings.TrimSpace(replace.New.Path + " " + replace.New.Version)
+	}
+
+	var modules []ModuleInfo
+	for _, req := range file.Require {
+		if req.Indirect {
+			continue
+		}
+		modules = append(modules, ModuleInfo{
+			Path:           req.Mod.Path,
+			CurrentVersion: req.Mod.Version,
+			Replacement:    replacements[req.Mod.Path],
+		})
+	}
+	return modules, nil
+}
+
+// ScanGoMod audits the direct requirements of a go.mod for combined scan reports; its table is
+// written once the findings are added to the report
+func ScanGoMod(router *forge.Router, filename string) ([]audit.Finding, audit.TableWriter, error) {
+	modules, err := readGoMod(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fmt.Printf("Starting audit of %d modules in %s...\n", len(modules), filename)
+	for i, mod := range modules {
+		fmt.Printf("-> Checking %s (%s)\n", mod.Path, mod.CurrentVersion)
+		modules[i] = checkModuleUpdate(router, mod)
+	}
+	table := func(writer *bufio.Writer, findings []audit.Finding) { writeTable(writer, modules, findings) }
+	return toFindings(modules), table, nil
+}
+
+// toFindings converts the audited modules into the ecosystem-neutral model
+func toFindings(modules []ModuleInfo) []audit.Finding {
+	var findings []audit.Finding
+	for _, mod := range modules {
+		var advisories []string
+		if mod.SecurityPatch {
+			advisories = append(advisories, "A newer release of "+mod.Path+" mentions a security fix")
+		}
+		findings = append(findings, audit.Finding{
+			Name:           mod.Path,
+			CurrentVersion: mod.CurrentVersion,
+			LatestVersion:  mod.LatestVersion,
+			Status:         mod.Status,
+			Compared:       mod.LatestVersion != "",
+			Error:          mod.Error,
+			UpdateNeeded:   mod.UpdateNeeded,
+			Vulnerable:     mod.SecurityPatch,
+			Bump:           mod.Bump,
+			ReleasesBehind: mod.ReleasesBehind,
+			Advisories:     advisories,
+		})
+	}
+	return findings
+}
+
+// Run audits the binaries given as arguments; without arguments it audits its own executable
+func Run(args []string) {
+	const outputFilePath = "gobinary/report.md"
+
+	// 1. Binaries to audit; without arguments the tool audits its own executable
+	binaries := args
+	if len(binaries) == 0 {
+		self, err := os.Executable()
+		if err != nil {
+			fmt.Printf("Fatal Error: %v\n", err)
+			os.Exit(1)
+		}
+		binaries = []string{self}
+	}
+
+	router := forge.NewRouter(forge.NewGitHubClient())
+
+	summary := audit.NewReport("gobinary", strings.Join(binaries, " "))
+	var reports []BinaryReport
+	for _, binary := range binaries {
+		started := time.Now()
+		report, err := readBinary(binary)
+		if err != nil {
+			fmt.Printf("⚠️ Warning: %v\n", err)
+			summary.Add(audit.NewSource(binary, "go", started, nil, err))
+			continue
+		}
+
+		// 2. Perform the checks (the main module is reported but not audited against itself)
+		fmt.Printf("Starting audit of %d modules in %s (%s)...\n", len(report.Modules)-1, binary, report.GoVersion)
+		for i, mod := range report.Modules {
+			if mod.Main {
+				report.Modules[i].Status = "🏠 Main module"
+				continue
+			}
+			fmt.Printf("-> Checking %s (%s)\n", mod.Path, mod.CurrentVersion)
+			report.Modules[i] = checkModuleUpdate(router, mod)
+		}
+		reports = append(reports, report)
+		summary.Add(audit.NewSource(binary, "go", started, toFindings(report.Modules), nil))
+	}
+
+	if len(reports) == 0 {
+		fmt.Println("No Go binaries with build info found to audit.")
+		os.Exit(1)
+	}
+
+	summary.Finish()
+
+	// 3. Write the final Markdown and JSON reports
+	err := printReport(reports, summary, outputFilePath)
+	if err == nil {
+		err = summary.WriteFiles(outputFilePath)
+	}
+	if err != nil {
+		fmt.Printf("Fatal Error writing report: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("✅ Operation completed successfully. Results saved in %s.\n", audit.OutputFiles(reportTemplate.Name, outputFilePath))
+}
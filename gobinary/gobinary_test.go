@@ -0,0 +1,38 @@
+package gobinary
+
+import "testing"
+
+func TestModuleProxyURL(t *testing.T) {
+	tests := []struct {
+		name      string
+		goproxy   string
+		goprivate string
+		gonoproxy string
+		module    string
+		want      string
+		reason    string
+	}{
+		{"default proxy", "", "", "", "github.com/spf13/cobra", "https://proxy.golang.org", ""},
+		{"first HTTP entry", "https://goproxy.example.com/,direct", "", "", "github.com/spf13/cobra", "https://goproxy.example.com", ""},
+		{"pipe separator", "https://a.example.com|https://b.example.com", "", "", "github.com/spf13/cobra", "https://a.example.com", ""},
+		{"off", "off", "", "", "github.com/spf13/cobra", "", "GOPROXY=off"},
+		{"direct", "direct", "", "", "github.com/spf13/cobra", "", "GOPROXY=direct"},
+		{"direct before a proxy", "direct,https://proxy.golang.org", "", "", "github.com/spf13/cobra", "", "GOPROXY=direct"},
+		{"GOPRIVATE prefix", "", "git.corp.example.com,github.com/acme/*", "", "git.corp.example.com/team/lib", "", "private module"},
+		{"GOPRIVATE glob", "", "git.corp.example.com,github.com/acme/*", "", "github.com/acme/billing/v2", "", "private module"},
+		{"GOPRIVATE other module", "", "github.com/acme/*", "", "github.com/acmeco/lib", "https://proxy.golang.org", ""},
+		{"GONOPROXY overrides GOPRIVATE", "", "github.com/acme/*", "git.corp.example.com", "github.com/acme/billing", "https://proxy.golang.org", ""},
+		{"GONOPROXY", "", "", "git.corp.example.com", "git.corp.example.com/team/lib", "", "private module"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv("GOPROXY", test.goproxy)
+			t.Setenv("GOPRIVATE", test.goprivate)
+			t.Setenv("GONOPROXY", test.gonoproxy)
+			got, reason := moduleProxyURL(test.module)
+			if got != test.want || reason != test.reason {
+				t.Errorf("moduleProxyURL(%q) = %q, %q, want %q, %q", test.module, got, reason, test.want, test.reason)
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+// Package toml reads the subset of TOML used by Cargo manifests and lockfiles and by Gradle
+// version catalogs: [table] and [[array]] headers with raw key/value pairs, which callers decode
+// with String, Array and InlineTable.
+package toml
+
+import (
+	"bufio"
+	"strings"
+)
+
+// Entry is one key of a table with its raw value
+type Entry struct {
+	Key   string
+	Value string // Raw TOML value (quoted string, inline table, array, bool...)
+}
+
+// Section is a [table] or [[array]] element and its keys, in document order
+type Section struct {
+	Name    string
+	Array   bool // [[name]] instead of [name]
+	Entries []Entry
+}
+
+// Parse: Splits a TOML document into sections with raw key/value pairs; the first section
+// holds the keys before any header
+func Parse(content string) []Section {
+	sections := []Section{{Name: ""}}
+	current := &sections[0]
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	pending := ""
+	for scanner.Scan() {
+		line := strings.TrimSpace(stripComment(scanner.Text()))
+		if pending != "" {
+			// Continuation of a multi-line array or inline table
+			pending += " " + line
+			if !valueComplete(pending) {
+				continue
+			}
+			line = pending
+			pending = ""
+		}
+		if line == "" {
+			continue
+		}
+
+		// Keys never start with '[', so such a line is a header, even when a quoted key
+		// inside it contains '=' (e.g. [target.'cfg(target_os = "linux")'.dependencies])
+		if name, array, ok := tableHeader(line); ok {
+			sections = append(sections, Section{Name: name, Array: array})
+			current = &sections[len(sections)-1]
+			continue
+		}
+
+		if !valueComplete(line) {
+			pending = line
+			continue
+		}
+
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			continue
+		}
+		current.Entries = append(current.Entries, Entry{
+			Key:   strings.Trim(strings.TrimSpace(key), `"`),
+			Value: strings.TrimSpace(value),
+		})
+	}
+	return sections
+}
+
+// tableHeader: Reads a [table] or [[array]] header; brackets inside quoted keys do not close it
+func tableHeader(line string) (name string, array bool, ok bool) {
+	if !strings.HasPrefix(line, "[") {
+		return "", false, false
+	}
+	array = strings.HasPrefix(line, "[[")
+	open := 1
+	if array {
+		open = 2
+	}
+
+	var quote rune
+	for i, r := range line[open:] {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == ']':
+			closing := "]"
+			if array {
+				closing = "]]"
+			}
+			rest := line[open+i:]
+			if !strings.HasPrefix(rest, closing) || strings.TrimSpace(rest[len(closing):]) != "" {
+				return "", false, false
+			}
+			return strings.TrimSpace(line[open : open+i]), array, true
+		}
+	}
+	return "", false, false
+}
+
+// stripComment: Removes a trailing '#' comment that is not inside a string
+func stripComment(line string) string {
+	inString := false
+	for i, r := range line {
+		switch {
+		case r == '"':
+			inString = !inString
+		case r == '#' && !inString:
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// valueComplete: Reports whether all brackets and braces in the line are closed
+func valueComplete(line string) bool {
+	depth := 0
+	inString := false
+	for _, r := range line {
+		switch {
+		case r == '"':
+			inString = !inString
+		case inString:
+		case r == '[' || r == '{':
+			depth++
+		case r == ']' || r == '}':
+			depth--
+		}
+	}
+	return depth <= 0
+}
+
+// splitList: Splits the inside of an array or inline table on top-level commas
+func splitList(inner string) []string {
+	var items []string
+	depth := 0
+	inString := false
+	start := 0
+	for i, r := range inner {
+		switch {
+		case r == '"':
+			inString = !inString
+		case inString:
+		case r == '[' || r == '{':
+			depth++
+		case r == ']' || r == '}':
+			depth--
+		case r == ',' && depth == 0:
+			items = append(items, strings.TrimSpace(inner[start:i]))
+			start = i + 1
+		}
+	}
+	if last := strings.TrimSpace(inner[start:]); last != "" {
+		items = append(items, last)
+	}
+	return items
+}
+
+// String: Unquotes a string value
+func String(value string) string {
+	return strings.Trim(strings.TrimSpace(value), `"'`)
+}
+
+// Array: Reads an array of strings
+func Array(value string) []string {
+	value = strings.TrimSpace(value)
+	value = strings.TrimSuffix(strings.TrimPrefix(value, "["), "]")
+	var items []string
+	for _, item := range splitList(value) {
+		items = append(items, String(item))
+	}
+	return items
+}
+
+// InlineTable: Reads { a = "x", b = { c = "y" } } into raw values by key
+func InlineTable(value string) map[string]string {
+	table := make(map[string]string)
+	value = strings.TrimSpace(value)
+	value = strings.TrimSuffix(strings.TrimPrefix(value, "{"), "}")
+	for _, item := range splitList(value) {
+		if key, val, found := strings.Cut(item, "="); found {
+			table[strings.TrimSpace(key)] = strings.TrimSpace(val)
+		}
+	}
+	return table
+}
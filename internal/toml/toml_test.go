@@ -0,0 +1,89 @@
+package toml
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseHeaders(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		array   bool
+		entries int
+	}{
+		{"plain table", "[dependencies]\nserde = \"1\"", "dependencies", false, 1},
+		{"array of tables", "[[package]]\nname = \"serde\"", "package", true, 1},
+		{"cfg target with =", "[target.'cfg(target_os = \"linux\")'.dependencies]\nlibc = \"0.2\"", "target.'cfg(target_os = \"linux\")'.dependencies", false, 1},
+		{"bracket inside quotes", "[target.\"cfg(any(unix, windows))\".dev-dependencies] # comment\nlibc = \"0.2\"", "target.\"cfg(any(unix, windows))\".dev-dependencies", false, 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sections := Parse(test.content)
+			if len(sections) != 2 {
+				t.Fatalf("got %d sections, want the root and one table: %+v", len(sections), sections)
+			}
+			section := sections[1]
+			if section.Name != test.want || section.Array != test.array || len(section.Entries) != test.entries {
+				t.Errorf("got %q (array %v, %d entries), want %q (array %v, %d entries)",
+					section.Name, section.Array, len(section.Entries), test.want, test.array, test.entries)
+			}
+			if len(sections[0].Entries) != 0 {
+				t.Errorf("header parsed as a root key: %+v", sections[0].Entries)
+			}
+		})
+	}
+}
+
+func TestInlineTable(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  map[string]string
+	}{
+		{"flat", `{ version = "1.0", features = ["derive", "std"] }`, map[string]string{"version": `"1.0"`, "features": `["derive", "std"]`}},
+		{"nested table", `{ module = "a:b", version = { strictly = "[1.0, 2.0)" } }`, map[string]string{"module": `"a:b"`, "version": `{ strictly = "[1.0, 2.0)" }`}},
+		{"comma inside a string", `{ path = "a,b", optional = true }`, map[string]string{"path": `"a,b"`, "optional": "true"}},
+		{"empty", `{}`, map[string]string{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := InlineTable(test.value)
+			if len(got) != len(test.want) {
+				t.Fatalf("got %v, want %v", got, test.want)
+			}
+			for key, value := range test.want {
+				if got[key] != value {
+					t.Errorf("%s = %q, want %q", key, got[key], value)
+				}
+			}
+		})
+	}
+}
+
+func TestParseValues(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		key     string
+		want    string
+	}{
+		{"comment after a value", "name = \"serde\" # the crate", "name", `"serde"`},
+		{"hash inside a string", "url = \"https://example.com/#main\"", "url", `"https://example.com/#main"`},
+		{"multi-line array", "members = [\n  \"a\",\n  \"b\", # second\n]", "members", `[ "a", "b", ]`},
+		{"multi-line inline table", "serde = {\n  version = \"1\",\n  features = [\"derive\"] }", "serde", `{ version = "1", features = ["derive"] }`},
+		{"quoted key", "\"serde-json\" = \"1\"", "serde-json", `"1"`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			entries := Parse(test.content)[0].Entries
+			if len(entries) != 1 || entries[0].Key != test.key || entries[0].Value != test.want {
+				t.Errorf("got %+v, want %s = %s", entries, test.key, test.want)
+			}
+		})
+	}
+	if got := Array(`[ "a", "b", ]`); strings.Join(got, ",") != "a,b" {
+		t.Errorf("Array = %q, want [a b]", got)
+	}
+}
@@ -2,42 +2,47 @@ package main
 
 import (
 	"bufio"
-	"context"
 	"fmt"
 	"os"
 	"strings"
+	"time"
+
+	"Sbom/audit"
+	"Sbom/backend"
+	"Sbom/cargo"
+	"Sbom/changelog"
+	"Sbom/container"
+	"Sbom/diff"
+	"Sbom/docker"
+	"Sbom/forge"
+	"Sbom/frontend"
+	"Sbom/gobinary"
+	"Sbom/maven"
+	"Sbom/version"
 
 	"github.com/google/go-github/v62/github"
-	"golang.org/x/mod/semver"
-	"golang.org/x/oauth2"
 )
 
 // UpdateInfo struct holds the update status and full changelog for each repository
 type UpdateInfo struct {
-	Repo             string
-	CurrentVersion   string
-	LatestVersion    string
-	UpdateNeeded     bool
-	SecurityPatch    bool
-	ReleaseNotesList []string // List to hold full changelog/release notes text for newer versions
-	Status           string
+	Repo            string
+	CurrentVersion  string
+	LatestVersion   string
+	UpdateNeeded    bool
+	SecurityPatch   bool
+	Changelog       changelog.Notes // Every release newer than the current version, newest first
+	BreakingChanges []changelog.BreakingItem
+	Advisories      []string // Skipped releases whose notes mention a security fix
+	Bump            version.Bump
+	ReleasesBehind  int    // Releases between the current and the latest version
+	Compared        bool   // The current version was compared against the latest release
+	Error           string // Why the repository could not be checked
+	Status          string
 }
 
-// createGitHubClient initializes the GitHub client, using a PAT if available.
+// createGitHubClient initializes the GitHub client (PAT, GitHub App or Enterprise, see forge.NewGitHubClient).
 func createGitHubClient() *github.Client {
-	ctx := context.Background()
-	token := os.Getenv("GITHUB_TOKEN")
-	if token == "" {
-		fmt.Println("⚠️ Warning: GITHUB_TOKEN environment variable not set. Using unauthenticated client (Severe rate limits apply).")
-		return github.NewClient(nil)
-	}
-
-	ts := oauth2.StaticTokenSource(
-		&oauth2.Token{AccessToken: token},
-	)
-	tc := oauth2.NewClient(ctx, ts)
-
-	return github.NewClient(tc)
+	return forge.NewGitHubClient()
 }
 
 // readRepos reads repository lines from the input file (format: owner/repo vX.Y.Z)
@@ -83,142 +88,252 @@ func parseLine(line string) (owner, repo, currentVer string) {
 }
 
 // checkUpdate checks for updates and security patches for a single repository
-func checkUpdate(client *github.Client, owner, repo, currentVer string) UpdateInfo {
+func checkUpdate(client *github.Client, owner, repo, currentVer string, scheme version.Scheme) UpdateInfo {
 	info := UpdateInfo{
 		Repo:           owner + "/" + repo,
 		CurrentVersion: currentVer,
 		LatestVersion:  "N/A",
 	}
 
+	source := forge.NewGitHub(client)
+	repository := forge.Repository{Host: source.Host(), Owner: owner, Name: repo}
+
 	// Fetch the list of latest releases
-	releases, _, err := client.Repositories.ListReleases(context.Background(), owner, repo, &github.ListOptions{
-		PerPage: 10, // Check up to 10 recent releases
-	})
+	releases, err := source.ListReleases(repository)
 
 	if err != nil {
-		info.Status = "❌ ERROR: " + err.Error()
+		info.Error = err.Error()
+		info.Status = "❌ ERROR: " + info.Error
 		return info
 	}
 
 	if len(releases) == 0 {
+		info.Error = "no releases found"
 		info.Status = "❌ ERROR: No releases found."
 		return info
 	}
 
-	latestRelease := releases[0]
-	latestVer := latestRelease.GetTagName()
+	latestVer := releases[0].TagName
 
 	if !strings.HasPrefix(latestVer, "v") {
 		latestVer = "v" + latestVer
 	}
 	info.LatestVersion = latestVer
+	info.Compared = true
 
-	if semver.Compare(info.CurrentVersion, info.LatestVersion) < 0 {
+	if scheme.Compare(info.CurrentVersion, info.LatestVersion) < 0 {
 		info.UpdateNeeded = true
+		info.Bump = scheme.Classify(info.CurrentVersion, info.LatestVersion)
 	} else {
 		info.Status = "✅ Up to date"
 		return info
 	}
 
-	// Check Release Notes and Security Patches (for newer releases)
-	for _, release := range releases {
-		tag := release.GetTagName()
-		if !strings.HasPrefix(tag, "v") {
-			tag = "v" + tag
-		}
-
-		if semver.Compare(info.CurrentVersion, tag) < 0 {
-			body := strings.ToLower(release.GetBody() + " " + release.GetName())
+	// Check Release Notes and Security Patches (every release since the current version, across pages)
+	notes, err := changelog.Collect(source, repository, scheme, info.CurrentVersion, info.LatestVersion)
+	if err != nil {
+		fmt.Printf("⚠️ Warning: Could not collect the changelog of %s: %v\n", info.Repo, err)
+	}
 
-			// Security Patch keywords check
-			if strings.Contains(body, "security") || strings.Contains(body, "vulnerability") || strings.Contains(body, "cve") || strings.Contains(body, "patch") {
-				info.SecurityPatch = true
-			}
+	var tags []string
+	for _, release := range notes.Releases {
+		tags = append(tags, changelog.TagVersion(release.Tag))
+		body := strings.ToLower(release.Body + " " + release.Name)
 
-			// ** Collect the full changelog body **
-			releaseDetail := fmt.Sprintf("\n--- Changelog for %s (%s) ---\n%s\n", release.GetName(), release.GetTagName(), release.GetBody())
-			info.ReleaseNotesList = append(info.ReleaseNotesList, releaseDetail)
+		// Security Patch keywords check
+		if strings.Contains(body, "security") || strings.Contains(body, "vulnerability") || strings.Contains(body, "cve") || strings.Contains(body, "patch") {
+			info.SecurityPatch = true
+			info.Advisories = append(info.Advisories, "Security fix in "+release.Tag)
 		}
 	}
+	info.Changelog = notes
+	info.BreakingChanges = changelog.Breaking(notes)
+
+	info.ReleasesBehind = scheme.Behind(info.CurrentVersion, info.LatestVersion, tags)
 
 	// Set final status
 	if info.SecurityPatch {
 		info.Status = "🚨 URGENT Update Required (Security Patch!)"
 	} else {
-		info.Status = "🔄 Update Recommended"
+		info.Status = audit.UpdateStatus(info.Bump)
 	}
 
 	return info
 }
 
-// writeOutput writes the results to an output file in Markdown format
-func writeOutput(infos []UpdateInfo, filename string) error {
-	// Ensure the filename ends with .md
-	if !strings.HasSuffix(filename, ".md") {
-		filename += ".md"
+// toFinding converts a repository check into the ecosystem-neutral model
+func toFinding(info UpdateInfo) audit.Finding {
+	return audit.Finding{
+		Name:            info.Repo,
+		CurrentVersion:  info.CurrentVersion,
+		LatestVersion:   info.LatestVersion,
+		Status:          info.Status,
+		Compared:        info.Compared,
+		Error:           info.Error,
+		UpdateNeeded:    info.UpdateNeeded,
+		Vulnerable:      info.SecurityPatch,
+		Bump:            info.Bump,
+		ReleasesBehind:  info.ReleasesBehind,
+		Advisories:      info.Advisories,
+		Changelog:       info.Changelog.Releases,
+		BreakingChanges: info.BreakingChanges,
 	}
+}
 
-	file, err := os.Create(filename)
-	if err != nil {
-		return fmt.Errorf("error creating output file: %w", err)
-	}
-	defer func(file *os.File) {
-		err := file.Close()
-		if err != nil {
-			fmt.Println("error closing output file:", err)
-		}
-	}(file)
+// reportTemplate renders one section per repository of input.txt, with its breaking changes and full changelog
+var reportTemplate = audit.Template{
+	Name: "repositories",
+	Text: `# 📈 GitHub Dependency Update Report
 
-	writer := bufio.NewWriter(file)
-	defer func(writer *bufio.Writer) {
-		err := writer.Flush()
-		if err != nil {
-			fmt.Println("error flushing output file:", err)
-		}
-	}(writer)
-
-	// Title
-	_, _ = writer.WriteString("# 📈 GitHub Dependency Update Report\n\n")
-	_, _ = writer.WriteString("This report summarizes the update status for all checked repositories.\n\n")
-	_, _ = writer.WriteString("---\n\n")
-
-	for _, info := range infos {
-		// Repository Heading
-		_, _ = writer.WriteString(fmt.Sprintf("## 📦 %s\n\n", info.Repo))
-
-		statusText := info.Status
-		if info.SecurityPatch {
-			statusText = "🚨 URGENT Security Patch!"
-		} else if info.UpdateNeeded {
-			statusText = "🔄 Update Recommended"
-		} else {
-			statusText = "✅ Up to date"
-		}
+This report summarizes the update status for all checked repositories.
+
+{{summary .Report}}---
+
+{{$findings := .Report.Findings}}{{range $i, $info := .Results}}## 📦 {{.Repo}}
+
+* **Status:** {{accepted (print "**" (status .) "**") (index $findings $i)}}
+* Current Version: {{code .CurrentVersion}}
+* Latest Version: {{code .LatestVersion}}
+* Change: {{changeLabel .Bump .ReleasesBehind}}
 
-		_, _ = writer.WriteString(fmt.Sprintf("* **Status:** **%s**\n", statusText))
-		_, _ = writer.WriteString(fmt.Sprintf("* Current Version: `%s`\n", info.CurrentVersion))
-		_, _ = writer.WriteString(fmt.Sprintf("* Latest Version: `%s`\n\n", info.LatestVersion))
+{{if .BreakingChanges}}### 💥 Breaking Changes & Migration Notes
 
-		if info.UpdateNeeded {
-			_, _ = writer.WriteString("### 📝 Full Changelog\n")
-			_, _ = writer.WriteString("> The following releases are newer than your current version. Changelog is ordered from newest to oldest.\n\n")
+{{breaking .BreakingChanges}}
+{{end}}{{if .UpdateNeeded}}### 📝 Full Changelog
+> The following releases are newer than your current version. Changelog is ordered from newest to oldest.
 
-			// Displaying the full changelog list in a markdown code block
-			for _, notes := range info.ReleaseNotesList {
-				_, _ = writer.WriteString("```markdown\n")
-				_, _ = writer.WriteString(notes)
-				_, _ = writer.WriteString("\n```\n\n")
+{{if .Changelog.Releases}}{{consolidate .Changelog}}{{else}}_No release notes found._
+
+{{end}}{{end}}---
+
+{{end}}`,
+	Funcs: map[string]interface{}{
+		"status": func(info UpdateInfo) string {
+			switch {
+			case info.SecurityPatch:
+				return "🚨 URGENT Security Patch!"
+			case info.UpdateNeeded:
+				return audit.UpdateStatus(info.Bump)
 			}
-		}
+			return "✅ Up to date"
+		},
+	},
+}
 
-		_, _ = writer.WriteString("---\n\n") // Separator
+// writeOutput renders the results ([]UpdateInfo) through reportTemplate
+func writeOutput(infos []UpdateInfo, report *audit.Report, filename string) error {
+	// Ensure the filename ends with .md
+	if !strings.HasSuffix(filename, ".md") {
+		filename += ".md"
 	}
-	return nil
+	return reportTemplate.Write(filename, audit.TemplateData{Report: report, Results: infos})
 }
 
 func main() {
 	const inputFile = "input.txt"
 	const outputFile = "output.md" // Output file set to Markdown
+	const workflowOutputFile = "workflows.md"
+	const scanOutputFile = "scan.md"
+	const fleetOutputFile = "fleet.md"
+	const diffOutputFile = "diff.md"
+	const baselineValidity = 90 * 24 * time.Hour // Default lifetime of accepted issues
+
+	// Subcommands select an ecosystem auditor; without one the repositories in input.txt are checked
+	if len(os.Args) > 1 {
+		args := os.Args[2:]
+		switch os.Args[1] {
+		case "frontend":
+			frontend.Run(args)
+			return
+		case "backend":
+			backend.Run(args)
+			return
+		case "cargo":
+			cargo.Run(args)
+			return
+		case "maven":
+			maven.Run(args)
+			return
+		case "docker":
+			docker.Run(args)
+			return
+		case "container":
+			container.Run(args)
+			return
+		case "gobinary":
+			gobinary.Run(args)
+			return
+		case "workflows":
+			// `workflows [dir]` audits the GitHub Actions used by a repository
+			root := "."
+			if len(args) > 0 {
+				root = args[0]
+			}
+			if err := runWorkflowAudit(root, workflowOutputFile); err != nil {
+				fmt.Printf("Fatal Error: %v\n", err)
+				return
+			}
+			fmt.Printf("✅ Operation completed successfully. Results saved in %s.\n", audit.OutputFiles(workflowTemplate.Name, workflowOutputFile))
+			return
+		case "scan":
+			// `scan [dir]` discovers every supported manifest and writes one combined report
+			root := "."
+			if len(args) > 0 {
+				root = args[0]
+			}
+			if err := runScan(root, scanOutputFile); err != nil {
+				fmt.Printf("Fatal Error: %v\n", err)
+				return
+			}
+			fmt.Printf("✅ Operation completed successfully. Results saved in %s.\n", audit.OutputFiles(scanTemplate.Name, scanOutputFile))
+			return
+		case "fleet":
+			// `fleet <org | repos-file>` scans many repositories through the GitHub API without cloning them
+			if len(args) < 1 {
+				fmt.Println("Usage: sbom fleet <org | file with owner/repo lines>")
+				os.Exit(1)
+			}
+			if err := runFleetScan(args[0], fleetOutputFile); err != nil {
+				fmt.Printf("Fatal Error: %v\n", err)
+				return
+			}
+			fmt.Printf("✅ Operation completed successfully. Results saved in %s.\n", audit.OutputFiles(fleetTemplate.Name, fleetOutputFile))
+			return
+		case "diff":
+			// `diff old.json new.json` compares two saved scans (JSON reports, CycloneDX or SPDX JSON)
+			if err := diff.Run(args, diffOutputFile); err != nil {
+				fmt.Printf("Fatal Error: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("✅ Operation completed successfully. Results saved in %s.\n", diff.OutputFiles(diffOutputFile))
+			return
+		case "baseline":
+			// `baseline <report.json> <owner> <reason> [YYYY-MM-DD]` accepts the issues of a report until the expiry date
+			if len(args) < 3 {
+				fmt.Println("Usage: sbom baseline <report.json> <owner> <reason> [expires YYYY-MM-DD, default in 90 days]")
+				os.Exit(1)
+			}
+			expires := time.Now().Add(baselineValidity)
+			if len(args) > 3 {
+				parsed, err := time.Parse("2006-01-02", args[3])
+				if err != nil {
+					fmt.Printf("Fatal Error: invalid expiry date '%s' (expected YYYY-MM-DD)\n", args[3])
+					os.Exit(1)
+				}
+				expires = parsed
+			}
+			added, err := audit.RecordBaseline(args[0], audit.BaselinePath(), args[1], args[2], expires)
+			if err != nil {
+				fmt.Printf("Fatal Error: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("✅ %d issues accepted until %s in %s.\n", added, expires.Format("2006-01-02"), audit.FileList([]string{audit.BaselinePath()}))
+			return
+		default:
+			fmt.Printf("Unknown command '%s'. Usage: sbom [frontend|backend|cargo|maven|docker|container|gobinary|workflows|scan|fleet|diff|baseline] [args...]\n", os.Args[1])
+			os.Exit(1)
+		}
+	}
 
 	client := createGitHubClient()
 
@@ -230,6 +345,8 @@ func main() {
 	}
 
 	var results []UpdateInfo
+	report := audit.NewReport("repositories", inputFile)
+	started := time.Now()
 
 	// 2. Process each repository
 	fmt.Printf("Starting check for %d repositories...\n", len(lines))
@@ -241,16 +358,26 @@ func main() {
 		}
 
 		fmt.Printf("-> Checking %s/%s (Current: %s)...\n", owner, repo, currentVer)
-		info := checkUpdate(client, owner, repo, currentVer)
+		info := checkUpdate(client, owner, repo, currentVer, version.For(ecosystemGitHub, owner+"/"+repo))
 		results = append(results, info)
 	}
 
+	var findings []audit.Finding
+	for _, info := range results {
+		findings = append(findings, toFinding(info))
+	}
+	report.Add(audit.NewSource(inputFile, ecosystemGitHub, started, findings, nil))
+	report.Finish()
+
 	// 3. Write output
-	err = writeOutput(results, outputFile)
+	err = writeOutput(results, report, outputFile)
+	if err == nil {
+		err = report.WriteFiles(outputFile)
+	}
 	if err != nil {
 		fmt.Printf("Fatal Error: %v\n", err)
 		return
 	}
 
-	fmt.Printf("✅ Operation completed successfully. Results saved in **%s**.\n", outputFile)
+	fmt.Printf("✅ Operation completed successfully. Results saved in %s.\n", audit.OutputFiles(reportTemplate.Name, outputFile))
 }
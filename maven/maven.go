@@ -0,0 +1,909 @@
+// Package maven audits pom.xml files and Gradle version catalogs against a Maven repository.
+package maven
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"Sbom/audit"
+	"Sbom/internal/toml"
+	"Sbom/version"
+)
+
+// --- Data Structures ---
+
+type MavenDependency struct {
+	GroupID        string
+	ArtifactID     string
+	CurrentVersion string
+	Scope          string
+	DeclaredIn     string // Manifest the dependency was read from
+	Managed        bool   // Version came from dependencyManagement (parent or BOM)
+	Plugin         bool   // Gradle plugin resolved through its plugin marker artifact
+	LatestVersion  string
+	UpdateNeeded   bool
+	Bump           version.Bump
+	ReleasesBehind int    // Released versions between the current and the latest version
+	Error          string // Why the artifact could not be checked
+	Status         string
+}
+
+type PomDependency struct {
+	GroupID    string `xml:"groupId"`
+	ArtifactID string `xml:"artifactId"`
+	Version    string `xml:"version"`
+	Type       string `xml:"type"`
+	Scope      string `xml:"scope"`
+}
+
+type PomParent struct {
+	GroupID      string  `xml:"groupId"`
+	ArtifactID   string  `xml:"artifactId"`
+	Version      string  `xml:"version"`
+	RelativePath *string `xml:"relativePath"`
+}
+
+// PomProperties holds the free-form <properties> block
+type PomProperties map[string]string
+
+type Pom struct {
+	Parent               PomParent     `xml:"parent"`
+	GroupID              string        `xml:"groupId"`
+	ArtifactID           string        `xml:"artifactId"`
+	Version              string        `xml:"version"`
+	Properties           PomProperties `xml:"properties"`
+	DependencyManagement struct {
+		Dependencies []PomDependency `xml:"dependencies>dependency"`
+	} `xml:"dependencyManagement"`
+	Dependencies []PomDependency `xml:"dependencies>dependency"`
+}
+
+type MavenMetadata struct {
+	Versioning struct {
+		Latest   string   `xml:"latest"`
+		Release  string   `xml:"release"`
+		Versions []string `xml:"versions>version"`
+	} `xml:"versioning"`
+}
+
+// effectivePom is a pom merged with its parents and imported BOMs
+type effectivePom struct {
+	Pom        Pom
+	Properties map[string]string
+	Managed    map[string]string // groupId:artifactId -> managed version
+}
+
+const defaultMavenRepository = "https://repo1.maven.org/maven2"
+const gradlePluginRepository = "https://plugins.gradle.org/m2"
+
+// UnmarshalXML: Collects every child element of <properties> as a key/value pair
+func (p *PomProperties) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	*p = make(PomProperties)
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			var value string
+			if err := d.DecodeElement(&value, &t); err != nil {
+				return err
+			}
+			(*p)[t.Name.Local] = strings.TrimSpace(value)
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// --- Maven Repository Client ---
+
+// mavenRepositoryURL: Uses MAVEN_REPOSITORY_URL (e.g. a local Nexus mirror) when set
+func mavenRepositoryURL() string {
+	if url := os.Getenv("MAVEN_REPOSITORY_URL"); url != "" {
+		return strings.TrimSuffix(url, "/")
+	}
+	return defaultMavenRepository
+}
+
+func fetchFromRepository(repository, path string) ([]byte, error) {
+	url := repository + "/" + path
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	// Nexus-style mirrors usually require credentials
+	if user := os.Getenv("MAVEN_REPOSITORY_USERNAME"); user != "" && repository == mavenRepositoryURL() {
+		req.SetBasicAuth(user, os.Getenv("MAVEN_REPOSITORY_PASSWORD"))
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("repository returned status %d for %s", resp.StatusCode, path)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+func fetchMetadata(repository, groupID, artifactID string) (*MavenMetadata, error) {
+	path := fmt.Sprintf("%s/%s/maven-metadata.xml", strings.ReplaceAll(groupID, ".", "/"), artifactID)
+	data, err := fetchFromRepository(repository, path)
+	if err != nil {
+		return nil, err
+	}
+	var metadata MavenMetadata
+	if err := xml.Unmarshal(data, &metadata); err != nil {
+		return nil, fmt.Errorf("error parsing maven-metadata.xml for %s:%s: %w", groupID, artifactID, err)
+	}
+	return &metadata, nil
+}
+
+func fetchRemotePom(groupID, artifactID, version string) (Pom, error) {
+	var pom Pom
+	path := fmt.Sprintf("%s/%s/%s/%s-%s.pom", strings.ReplaceAll(groupID, ".", "/"), artifactID, version, artifactID, version)
+	data, err := fetchFromRepository(mavenRepositoryURL(), path)
+	if err != nil {
+		return pom, err
+	}
+	if err := xml.Unmarshal(data, &pom); err != nil {
+		return pom, fmt.Errorf("error parsing pom %s:%s:%s: %w", groupID, artifactID, version, err)
+	}
+	return pom, nil
+}
+
+// --- Maven Version Ordering ---
+
+// mavenItem is one token of a parsed version: an integer, a qualifier or a nested list
+type mavenItem struct {
+	isInt bool
+	num   int64
+	str   string
+	list  []mavenItem
+	isSub bool
+}
+
+var qualifierOrder = []string{"alpha", "beta", "milestone", "rc", "snapshot", "", "sp"}
+
+var qualifierAliases = map[string]string{"ga": "", "final": "", "release": "", "cr": "rc"}
+
+// parseMavenVersion: Tokenizes a version following Maven's ComparableVersion rules:
+// '.' separates items, '-' and digit/letter transitions open a nested list.
+func parseMavenVersion(version string) []mavenItem {
+	version = strings.ToLower(version)
+	root := &mavenItem{isSub: true}
+	current := root
+
+	newItem := func(isDigit bool, token string, followedByDigit bool) mavenItem {
+		if isDigit {
+			n, _ := strconv.ParseInt(token, 10, 64)
+			return mavenItem{isInt: true, num: n}
+		}
+		if followedByDigit && len(token) == 1 {
+			switch token {
+			case "a":
+				token = "alpha"
+			case "b":
+				token = "beta"
+			case "m":
+				token = "milestone"
+			}
+		}
+		if alias, ok := qualifierAliases[token]; ok {
+			token = alias
+		}
+		return mavenItem{str: token}
+	}
+	openList := func() {
+		current.list = append(current.list, mavenItem{isSub: true})
+		current = &current.list[len(current.list)-1]
+	}
+
+	isDigit := false
+	start := 0
+	for i := 0; i < len(version); i++ {
+		c := version[i]
+		switch {
+		case c == '.' || c == '-':
+			if i == start {
+				current.list = append(current.list, mavenItem{isInt: true})
+			} else {
+				current.list = append(current.list, newItem(isDigit, version[start:i], false))
+			}
+			start = i + 1
+			if c == '-' {
+				openList()
+			}
+		case c >= '0' && c <= '9':
+			if !isDigit && i > start {
+				current.list = append(current.list, newItem(false, version[start:i], true))
+				start = i
+				openList()
+			}
+			isDigit = true
+		default:
+			if isDigit && i > start {
+				current.list = append(current.list, newItem(true, version[start:i], false))
+				start = i
+				openList()
+			}
+			isDigit = false
+		}
+	}
+	if len(version) > start {
+		current.list = append(current.list, newItem(isDigit, version[start:], false))
+	}
+
+	normalizeMavenItems(root)
+	return root.list
+}
+
+// normalizeMavenItems: Drops trailing "null" items (0, "", empty lists) so that 1.0 == 1 == 1-ga.
+// Like Maven's ListItem.normalize, it walks back past trailing sublists, so 1.0-alpha == 1-alpha.
+func normalizeMavenItems(item *mavenItem) {
+	for i := range item.list {
+		if item.list[i].isSub {
+			normalizeMavenItems(&item.list[i])
+		}
+	}
+	for i := len(item.list) - 1; i >= 0; i-- {
+		switch {
+		case isNullMavenItem(item.list[i]):
+			item.list = append(item.list[:i], item.list[i+1:]...)
+		case !item.list[i].isSub:
+			return
+		}
+	}
+}
+
+func isNullMavenItem(item mavenItem) bool {
+	switch {
+	case item.isInt:
+		return item.num == 0
+	case item.isSub:
+		return len(item.list) == 0
+	default:
+		return item.str == ""
+	}
+}
+
+func qualifierRank(q string) string {
+	for i, known := range qualifierOrder {
+		if q == known {
+			return strconv.Itoa(i)
+		}
+	}
+	// Unknown qualifiers sort after all known ones, lexically
+	return fmt.Sprintf("%d-%s", len(qualifierOrder), q)
+}
+
+// compareMavenItem: Compares an item against another (nil means "missing")
+func compareMavenItem(a mavenItem, b *mavenItem) int {
+	switch {
+	case a.isInt:
+		if b == nil {
+			if a.num == 0 {
+				return 0
+			}
+			return 1
+		}
+		if b.isInt {
+			switch {
+			case a.num < b.num:
+				return -1
+			case a.num > b.num:
+				return 1
+			}
+			return 0
+		}
+		return 1 // Integers are newer than qualifiers and lists
+
+	case a.isSub:
+		if b == nil {
+			if len(a.list) == 0 {
+				return 0
+			}
+			return compareMavenItem(a.list[0], nil)
+		}
+		if b.isInt {
+			return -1
+		}
+		if !b.isSub {
+			return 1
+		}
+		return compareMavenLists(a.list, b.list)
+
+	default:
+		if b == nil {
+			return strings.Compare(qualifierRank(a.str), qualifierRank(""))
+		}
+		if b.isInt || b.isSub {
+			return -1
+		}
+		return strings.Compare(qualifierRank(a.str), qualifierRank(b.str))
+	}
+}
+
+func compareMavenLists(a, b []mavenItem) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var result int
+		switch {
+		case i >= len(a):
+			result = -compareMavenItem(b[i], nil)
+		case i >= len(b):
+			result = compareMavenItem(a[i], nil)
+		default:
+			result = compareMavenItem(a[i], &b[i])
+		}
+		if result != 0 {
+			return result
+		}
+	}
+	return 0
+}
+
+// compareMavenVersions: Returns -1, 0 or 1 using Maven's version ordering
+func compareMavenVersions(a, b string) int {
+	return compareMavenLists(parseMavenVersion(a), parseMavenVersion(b))
+}
+
+var preReleasePattern = regexp.MustCompile(`(?i)(alpha|beta|milestone|snapshot|preview|[.-]rc\d*|[.-]cr\d*|[.-]m\d+|[.-]ea\b|\d(a|b)\d)`)
+
+func isMavenPreRelease(version string) bool {
+	return preReleasePattern.MatchString(version)
+}
+
+// latestMavenVersion: Picks the highest release from maven-metadata.xml. Pre-releases are
+// only considered when the current version is itself a pre-release.
+func latestMavenVersion(metadata *MavenMetadata, currentVer string) string {
+	allowPreRelease := isMavenPreRelease(currentVer)
+	latest := ""
+	for _, ver := range metadata.Versioning.Versions {
+		if strings.HasSuffix(strings.ToUpper(ver), "-SNAPSHOT") {
+			continue
+		}
+		if isMavenPreRelease(ver) && !allowPreRelease {
+			continue
+		}
+		if latest == "" || compareMavenVersions(ver, latest) > 0 {
+			latest = ver
+		}
+	}
+	if latest == "" {
+		latest = metadata.Versioning.Release
+	}
+	return latest
+}
+
+var numericPrefixPattern = regexp.MustCompile(`^\d+(\.\d+)*`)
+
+// classifyMavenUpdate: Classifies by the numeric part of both versions (5.3.2.RELEASE → 5.3.2);
+// a change in the qualifier only (-jre, .Final) counts as a patch
+func classifyMavenUpdate(artifact, currentVer, latestVer string) version.Bump {
+	current, latest := numericPrefixPattern.FindString(currentVer), numericPrefixPattern.FindString(latestVer)
+	if current == "" || latest == "" {
+		return version.NoBump
+	}
+	if bump := version.For("maven", artifact).Classify(current, latest); bump != version.NoBump {
+		return bump
+	}
+	return version.Patch
+}
+
+// releasesBehind: Counts the released versions after currentVer, up to and including latestVer
+func releasesBehind(metadata *MavenMetadata, currentVer, latestVer string) int {
+	allowPreRelease := isMavenPreRelease(latestVer)
+	count := 0
+	for _, ver := range metadata.Versioning.Versions {
+		if strings.HasSuffix(strings.ToUpper(ver), "-SNAPSHOT") || (isMavenPreRelease(ver) && !allowPreRelease) {
+			continue
+		}
+		if compareMavenVersions(ver, currentVer) > 0 && compareMavenVersions(ver, latestVer) <= 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// --- pom.xml Parsing ---
+
+func readPom(filename string) (Pom, error) {
+	var pom Pom
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return pom, fmt.Errorf("error reading %s: %w", filename, err)
+	}
+	if err := xml.Unmarshal(data, &pom); err != nil {
+		return pom, fmt.Errorf("error parsing %s: %w", filename, err)
+	}
+	return pom, nil
+}
+
+var propertyPattern = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+// interpolate: Replaces ${...} references, following nested properties a few levels deep
+func interpolate(value string, props map[string]string) string {
+	for i := 0; i < 10 && strings.Contains(value, "${"); i++ {
+		replaced := propertyPattern.ReplaceAllStringFunc(value, func(ref string) string {
+			if val, ok := props[ref[2:len(ref)-1]]; ok {
+				return val
+			}
+			return ref
+		})
+		if replaced == value {
+			break
+		}
+		value = replaced
+	}
+	return value
+}
+
+// loadParentPom: Reads the parent from relativePath (default ../pom.xml) when its coordinates
+// match, otherwise downloads it from the repository.
+func loadParentPom(pomPath string, parent PomParent) (Pom, string, error) {
+	relativePath := "../pom.xml"
+	if parent.RelativePath != nil {
+		relativePath = strings.TrimSpace(*parent.RelativePath)
+	}
+	if relativePath != "" {
+		candidate := filepath.Join(filepath.Dir(pomPath), relativePath)
+		if info, err := os.Stat(candidate); err == nil && info.IsDir() {
+			candidate = filepath.Join(candidate, "pom.xml")
+		}
+		if local, err := readPom(candidate); err == nil && local.ArtifactID == parent.ArtifactID {
+			return local, candidate, nil
+		}
+	}
+	remote, err := fetchRemotePom(parent.GroupID, parent.ArtifactID, parent.Version)
+	return remote, "", err
+}
+
+// resolveEffectivePom: Merges properties and dependencyManagement from the parent chain and
+// imported BOMs. Child values override inherited ones, as in Maven.
+func resolveEffectivePom(pom Pom, pomPath string, depth int) effectivePom {
+	effective := effectivePom{
+		Pom:        pom,
+		Properties: make(map[string]string),
+		Managed:    make(map[string]string),
+	}
+	if depth > 10 {
+		return effective
+	}
+
+	if pom.Parent.ArtifactID != "" {
+		parentPom, parentPath, err := loadParentPom(pomPath, pom.Parent)
+		if err != nil {
+			fmt.Printf("⚠️ Warning: Could not load parent %s:%s:%s: %v\n", pom.Parent.GroupID, pom.Parent.ArtifactID, pom.Parent.Version, err)
+		} else {
+			parent := resolveEffectivePom(parentPom, parentPath, depth+1)
+			for k, v := range parent.Properties {
+				effective.Properties[k] = v
+			}
+			for k, v := range parent.Managed {
+				effective.Managed[k] = v
+			}
+		}
+	}
+
+	// groupId and version are inherited from <parent> when omitted
+	groupID := firstNonEmpty(pom.GroupID, pom.Parent.GroupID)
+	version := firstNonEmpty(pom.Version, pom.Parent.Version)
+	for k, v := range pom.Properties {
+		effective.Properties[k] = v
+	}
+	effective.Properties["project.groupId"] = groupID
+	effective.Properties["project.artifactId"] = pom.ArtifactID
+	effective.Properties["project.version"] = version
+	effective.Properties["pom.version"] = version
+	effective.Properties["project.parent.groupId"] = pom.Parent.GroupID
+	effective.Properties["project.parent.version"] = pom.Parent.Version
+
+	for _, dep := range pom.DependencyManagement.Dependencies {
+		groupID := interpolate(dep.GroupID, effective.Properties)
+		version := interpolate(dep.Version, effective.Properties)
+
+		if dep.Scope == "import" && dep.Type == "pom" {
+			bom, err := fetchRemotePom(groupID, dep.ArtifactID, version)
+			if err != nil {
+				fmt.Printf("⚠️ Warning: Could not import BOM %s:%s:%s: %v\n", groupID, dep.ArtifactID, version, err)
+				continue
+			}
+			imported := resolveEffectivePom(bom, "", depth+1)
+			for k, v := range imported.Managed {
+				// Explicitly managed versions win over BOM imports
+				if _, exists := effective.Managed[k]; !exists {
+					effective.Managed[k] = v
+				}
+			}
+			continue
+		}
+		effective.Managed[groupID+":"+dep.ArtifactID] = version
+	}
+	return effective
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func parsePomDependencies(filename string) ([]MavenDependency, error) {
+	pom, err := readPom(filename)
+	if err != nil {
+		return nil, err
+	}
+	effective := resolveEffectivePom(pom, filename, 0)
+
+	var deps []MavenDependency
+	for _, dep := range pom.Dependencies {
+		groupID := interpolate(dep.GroupID, effective.Properties)
+		artifactID := interpolate(dep.ArtifactID, effective.Properties)
+
+		md := MavenDependency{
+			GroupID:    groupID,
+			ArtifactID: artifactID,
+			Scope:      firstNonEmpty(dep.Scope, "compile"),
+			DeclaredIn: filename,
+		}
+		if dep.Version != "" {
+			md.CurrentVersion = interpolate(dep.Version, effective.Properties)
+		} else if managed, ok := effective.Managed[groupID+":"+artifactID]; ok {
+			md.CurrentVersion = managed
+			md.Managed = true
+		}
+		deps = append(deps, md)
+	}
+	return deps, nil
+}
+
+// --- Gradle Version Catalog Parsing ---
+
+// parseVersionCatalog: Reads [versions], [libraries] and [plugins] from libs.versions.toml
+func parseVersionCatalog(filename string) ([]MavenDependency, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", filename, err)
+	}
+
+	sections := make(map[string][][2]string)
+	for _, section := range toml.Parse(string(data)) {
+		for _, entry := range section.Entries {
+			sections[section.Name] = append(sections[section.Name], [2]string{entry.Key, entry.Value})
+		}
+	}
+
+	// Version aliases may be plain strings or rich versions ({ strictly = "..." })
+	versions := make(map[string]string)
+	for _, kv := range sections["versions"] {
+		versions[kv[0]] = catalogVersion(kv[1], nil)
+	}
+
+	var deps []MavenDependency
+	for _, kv := range sections["libraries"] {
+		value := kv[1]
+		var groupID, artifactID, version string
+		if !strings.HasPrefix(value, "{") {
+			// Short notation: "group:artifact:version"
+			parts := strings.Split(toml.String(value), ":")
+			if len(parts) < 2 {
+				continue
+			}
+			groupID, artifactID = parts[0], parts[1]
+			if len(parts) > 2 {
+				version = parts[2]
+			}
+		} else {
+			table := toml.InlineTable(value)
+			if module, ok := table["module"]; ok {
+				groupID, artifactID, _ = strings.Cut(toml.String(module), ":")
+			} else {
+				groupID, artifactID = toml.String(table["group"]), toml.String(table["name"])
+			}
+			version = catalogVersion(value, versions)
+		}
+		deps = append(deps, MavenDependency{
+			GroupID:        groupID,
+			ArtifactID:     artifactID,
+			CurrentVersion: version,
+			Scope:          "library",
+			DeclaredIn:     filename,
+		})
+	}
+
+	for _, kv := range sections["plugins"] {
+		table := toml.InlineTable(kv[1])
+		id := toml.String(table["id"])
+		if id == "" {
+			continue
+		}
+		// Plugins are published under the marker artifact <id>:<id>.gradle.plugin
+		deps = append(deps, MavenDependency{
+			GroupID:        id,
+			ArtifactID:     id + ".gradle.plugin",
+			CurrentVersion: catalogVersion(kv[1], versions),
+			Scope:          "plugin",
+			DeclaredIn:     filename,
+			Plugin:         true,
+		})
+	}
+	return deps, nil
+}
+
+// catalogVersion: Resolves a catalog version declaration: "1.0", { version = "1.0" },
+// { version.ref = "x" }, { version = { ref = "x" } } or { strictly/require/prefer = "1.0" }.
+func catalogVersion(value string, versions map[string]string) string {
+	value = strings.TrimSpace(value)
+	if !strings.HasPrefix(value, "{") {
+		return toml.String(value)
+	}
+	table := toml.InlineTable(value)
+	if ref, ok := table["version.ref"]; ok {
+		return versions[toml.String(ref)]
+	}
+	if version, ok := table["version"]; ok {
+		if strings.HasPrefix(version, "{") {
+			return catalogVersion(version, versions)
+		}
+		return toml.String(version)
+	}
+	if ref, ok := table["ref"]; ok {
+		return versions[toml.String(ref)]
+	}
+	for _, key := range []string{"strictly", "require", "prefer"} {
+		if v, ok := table[key]; ok {
+			return toml.String(v)
+		}
+	}
+	return ""
+}
+
+// --- Core Check Logic ---
+
+func checkMavenUpdate(dep MavenDependency, cache map[string]*MavenMetadata) MavenDependency {
+	if dep.CurrentVersion == "" {
+		dep.Error = "version not declared or managed"
+		dep.Status = "❌ Version not declared or managed"
+		return dep
+	}
+	if strings.Contains(dep.CurrentVersion, "${") {
+		dep.Error = "unresolved property " + dep.CurrentVersion
+		dep.Status = "❌ Unresolved property " + dep.CurrentVersion
+		return dep
+	}
+
+	repository := mavenRepositoryURL()
+	if dep.Plugin {
+		repository = gradlePluginRepository
+	}
+
+	key := repository + "|" + dep.GroupID + ":" + dep.ArtifactID
+	metadata, ok := cache[key]
+	if !ok {
+		var err error
+		metadata, err = fetchMetadata(repository, dep.GroupID, dep.ArtifactID)
+		if err != nil {
+			dep.Error = err.Error()
+			dep.Status = "❌ Error: " + dep.Error
+			return dep
+		}
+		cache[key] = metadata
+	}
+
+	dep.LatestVersion = latestMavenVersion(metadata, dep.CurrentVersion)
+	if dep.LatestVersion == "" {
+		dep.Error = "no released versions found"
+		dep.Status = "❌ Error: " + dep.Error
+		return dep
+	}
+
+	if compareMavenVersions(dep.CurrentVersion, dep.LatestVersion) < 0 {
+		dep.UpdateNeeded = true
+		dep.Bump = classifyMavenUpdate(dep.GroupID+":"+dep.ArtifactID, dep.CurrentVersion, dep.LatestVersion)
+		dep.ReleasesBehind = releasesBehind(metadata, dep.CurrentVersion, dep.LatestVersion)
+		dep.Status = audit.UpdateStatus(dep.Bump)
+	} else {
+		dep.Status = "✅ Up to Date"
+	}
+	return dep
+}
+
+// --- Output Function (Markdown Table) ---
+
+// reportTemplate is the default artifact report; it names the repository the versions came from
+var reportTemplate = audit.Template{
+	Name: "maven",
+	Text: `## ☕ Java Dependency Update Audit
+
+This report compares the versions in your {{code "pom.xml"}} and Gradle version catalog against {{code repositoryURL}}.
+
+{{summary .Report}}{{table .Results .Report.Findings}}`,
+	Funcs: map[string]interface{}{
+		"repositoryURL": mavenRepositoryURL,
+		"table": func(results []MavenDependency, findings []audit.Finding) string {
+			return audit.Capture(func(writer *bufio.Writer) { writeTable(writer, results, findings) })
+		},
+	},
+}
+
+// printReport: Renders the results ([]MavenDependency) through reportTemplate
+func printReport(results []MavenDependency, report *audit.Report, filename string) error {
+	return reportTemplate.Write(filename, audit.TemplateData{Report: report, Results: results})
+}
+
+// writeTable writes the artifact table (shared with combined scan reports) from the report's
+// findings; results, in the same order, add the scopes and declaring manifests
+func writeTable(writer *bufio.Writer, results []MavenDependency, findings []audit.Finding) {
+	writer.WriteString("| # | Artifact | Scope | Status | Change | Current Version | Latest Version | Declared In |\n")
+	writer.WriteString("| :---: | :--- | :---: | :---: | :---: | :---: | :---: | :--- |\n")
+
+	for i, finding := range findings {
+		dep := results[i]
+		statusDisplay := finding.Status
+		if finding.UpdateNeeded {
+			statusDisplay = "**" + statusDisplay + "**"
+		}
+		statusDisplay = audit.AcceptedStatus(statusDisplay, finding)
+
+		currentDisplay := "`" + finding.CurrentVersion + "`"
+		if dep.Managed {
+			currentDisplay += " (managed)"
+		}
+
+		line := fmt.Sprintf("| %d | `%s` | %s | %s | %s | %s | `%s` | `%s` |\n",
+			i+1, finding.Name, dep.Scope, statusDisplay, audit.ChangeLabel(finding.Bump, finding.ReleasesBehind), currentDisplay, finding.LatestVersion, dep.DeclaredIn)
+		writer.WriteString(line)
+	}
+}
+
+// checkDependencies runs the repository checks, fetching each artifact's metadata only once
+func checkDependencies(deps []MavenDependency) []MavenDependency {
+	sort.SliceStable(deps, func(i, j int) bool { return deps[i].DeclaredIn < deps[j].DeclaredIn })
+
+	fmt.Printf("Starting audit of %d Java dependencies against %s...\n", len(deps), mavenRepositoryURL())
+
+	cache := make(map[string]*MavenMetadata)
+	var results []MavenDependency
+	for _, dep := range deps {
+		fmt.Printf("-> Checking %s:%s (%s)\n", dep.GroupID, dep.ArtifactID, dep.CurrentVersion)
+		results = append(results, checkMavenUpdate(dep, cache))
+	}
+	return results
+}
+
+// declaringManifests lists the distinct manifests the dependencies were read from, sorted
+func declaringManifests(deps []MavenDependency) []string {
+	seen := make(map[string]bool)
+	var manifests []string
+	for _, dep := range deps {
+		if !seen[dep.DeclaredIn] {
+			seen[dep.DeclaredIn] = true
+			manifests = append(manifests, dep.DeclaredIn)
+		}
+	}
+	sort.Strings(manifests)
+	return manifests
+}
+
+// ScanManifest audits one pom.xml or libs.versions.toml for combined scan reports; its table is
+// written once the findings are added to the report
+func ScanManifest(filename string) ([]audit.Finding, audit.TableWriter, error) {
+	var deps []MavenDependency
+	var err error
+	if strings.HasSuffix(filename, ".toml") {
+		deps, err = parseVersionCatalog(filename)
+	} else {
+		deps, err = parsePomDependencies(filename)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	results := checkDependencies(deps)
+	table := func(writer *bufio.Writer, findings []audit.Finding) { writeTable(writer, results, findings) }
+	return toFindings(results), table, nil
+}
+
+// toFindings converts the audited artifacts into the ecosystem-neutral model
+func toFindings(results []MavenDependency) []audit.Finding {
+	var findings []audit.Finding
+	for _, dep := range results {
+		findings = append(findings, audit.Finding{
+			Name:           dep.GroupID + ":" + dep.ArtifactID,
+			CurrentVersion: dep.CurrentVersion,
+			LatestVersion:  dep.LatestVersion,
+			Status:         dep.Status,
+			Compared:       dep.LatestVersion != "",
+			Error:          dep.Error,
+			UpdateNeeded:   dep.UpdateNeeded,
+			Bump:           dep.Bump,
+			ReleasesBehind: dep.ReleasesBehind,
+		})
+	}
+	return findings
+}
+
+// Run audits maven/pom.xml and maven/gradle/libs.versions.toml (or the files given as arguments)
+func Run(args []string) {
+	pomFileName := "maven/pom.xml"
+	catalogFileName := "maven/gradle/libs.versions.toml"
+	if len(args) > 0 {
+		pomFileName, catalogFileName = "", ""
+		for _, arg := range args {
+			if strings.HasSuffix(arg, ".toml") {
+				catalogFileName = arg
+			} else {
+				pomFileName = arg
+			}
+		}
+	}
+	const outputFilePath = "maven/report.md"
+
+	var deps []MavenDependency
+
+	// 1. Parse pom.xml (including parents and BOMs) and the Gradle version catalog
+	if pomFileName != "" {
+		pomDeps, err := parsePomDependencies(pomFileName)
+		if err != nil {
+			fmt.Printf("⚠️ Warning: %v\n", err)
+		}
+		deps = append(deps, pomDeps...)
+	}
+	if catalogFileName != "" {
+		catalogDeps, err := parseVersionCatalog(catalogFileName)
+		if err != nil {
+			fmt.Printf("⚠️ Warning: %v\n", err)
+		}
+		deps = append(deps, catalogDeps...)
+	}
+
+	if len(deps) == 0 {
+		fmt.Println("No Maven or Gradle dependencies found to audit.")
+		return
+	}
+
+	// 2. Perform the checks, one manifest at a time so the JSON report can attribute them
+	report := audit.NewReport("maven", strings.TrimSpace(pomFileName+" "+catalogFileName))
+	var results []MavenDependency
+	for _, manifest := range declaringManifests(deps) {
+		started := time.Now()
+		var manifestDeps []MavenDependency
+		for _, dep := range deps {
+			if dep.DeclaredIn == manifest {
+				manifestDeps = append(manifestDeps, dep)
+			}
+		}
+		checked := checkDependencies(manifestDeps)
+		report.Add(audit.NewSource(manifest, "maven", started, toFindings(checked), nil))
+		results = append(results, checked...)
+	}
+	report.Finish()
+
+	// 3. Write the final Markdown and JSON reports
+	err := printReport(results, report, outputFilePath)
+	if err == nil {
+		err = report.WriteFiles(outputFilePath)
+	}
+	if err != nil {
+		fmt.Printf("Fatal Error writing report: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("✅ Operation completed successfully. Results saved in %s.\n", audit.OutputFiles(reportTemplate.Name, outputFilePath))
+}
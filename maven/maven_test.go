@@ -0,0 +1,71 @@
+package maven
+
+import "testing"
+
+func TestCompareMavenVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1", "1.0", 0},
+		{"1.0.0", "1-ga", 0},
+		{"1-final", "1.0-release", 0},
+		{"2.0.0.RELEASE", "2.0.0", 0},
+		{"1-a1", "1-alpha-1", 0},
+		{"1-cr1", "1-rc1", 0},
+		{"1.0-alpha", "1-alpha", 0},
+		{"1.0.0-rc1", "1-rc1", 0},
+		{"1.0-1", "1-1", 0},
+		{"2.0-jre", "2-jre", 0},
+		{"1.9", "1.10", -1},
+		{"1", "1.1", -1},
+		{"1-alpha", "1-beta", -1},
+		{"1-beta", "1-milestone", -1},
+		{"1-milestone", "1-rc", -1},
+		{"1-rc", "1-snapshot", -1},
+		{"1-snapshot", "1", -1},
+		{"1", "1-sp", -1},
+		{"1-sp", "1.0.1", -1},
+		{"1", "1-xyz", -1},
+		{"1-alpha-1", "1-alpha-2", -1},
+		{"1.0.0-M1", "1.0.0-RC1", -1},
+		{"1.0-RC1", "1.0", -1},
+		{"1.0", "1.0-1", -1},
+		{"30.1-jre", "31.0-jre", -1},
+		{"31.0-android", "31.0-jre", -1},
+		{"5.3.2.RELEASE", "5.3.10.RELEASE", -1},
+	}
+	for _, test := range tests {
+		t.Run(test.a+" vs "+test.b, func(t *testing.T) {
+			if got := compareMavenVersions(test.a, test.b); got != test.want {
+				t.Errorf("compareMavenVersions(%q, %q) = %d, want %d", test.a, test.b, got, test.want)
+			}
+			if got := compareMavenVersions(test.b, test.a); got != -test.want {
+				t.Errorf("compareMavenVersions(%q, %q) = %d, want %d", test.b, test.a, got, -test.want)
+			}
+		})
+	}
+}
+
+func TestLatestMavenVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		versions []string
+		current  string
+		want     string
+	}{
+		{"numeric order, not lexical", []string{"1.9.0", "1.10.0", "1.2.0"}, "1.2.0", "1.10.0"},
+		{"skips pre-releases and snapshots", []string{"2.0.0", "2.1.0-RC1", "2.1.0-SNAPSHOT", "3.0.0-M2"}, "1.0.0", "2.0.0"},
+		{"pre-releases for a pre-release", []string{"2.0.0", "2.1.0-RC1", "2.1.0-M1"}, "2.1.0-M1", "2.1.0-RC1"},
+		{"release qualifiers", []string{"5.3.9.RELEASE", "5.3.10.RELEASE"}, "5.3.9.RELEASE", "5.3.10.RELEASE"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			metadata := &MavenMetadata{}
+			metadata.Versioning.Versions = test.versions
+			if got := latestMavenVersion(metadata, test.current); got != test.want {
+				t.Errorf("latestMavenVersion(%v, %q) = %q, want %q", test.versions, test.current, got, test.want)
+			}
+		})
+	}
+}
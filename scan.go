@@ -0,0 +1,316 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"Sbom/audit"
+	"Sbom/backend"
+	"Sbom/cargo"
+	"Sbom/docker"
+	"Sbom/forge"
+	"Sbom/frontend"
+	"Sbom/gobinary"
+	"Sbom/maven"
+
+	"github.com/google/go-github/v62/github"
+)
+
+// Manifest is a dependency file found while walking a repository
+type Manifest struct {
+	Path      string
+	Ecosystem string
+}
+
+// ignoreRule is one line of a .gitignore, relative to the directory holding that file
+type ignoreRule struct {
+	Base     string // Directory of the .gitignore, relative to the scan root ("." for the root)
+	Pattern  string
+	Negate   bool // "!pattern" re-includes a previously ignored path
+	DirOnly  bool // "pattern/" only matches directories
+	Anchored bool // A pattern containing a '/' is matched against the whole relative path
+}
+
+const (
+	ecosystemNpm       = "npm"
+	ecosystemRebar     = "rebar"
+	ecosystemCargo     = "cargo"
+	ecosystemMaven     = "maven"
+	ecosystemDocker    = "docker"
+	ecosystemGo        = "go"
+	ecosystemWorkflows = "github-actions"
+	ecosystemGitHub    = "github" // The repositories of input.txt, checked against their GitHub releases
+)
+
+// skippedDirs are never descended into, whether or not a .gitignore lists them
+var skippedDirs = map[string]bool{
+	".git":         true,
+	"node_modules": true,
+	"_build":       true,
+}
+
+// --- .gitignore Handling ---
+
+// readIgnoreRules parses the .gitignore in dir, if any
+func readIgnoreRules(root, dir string) []ignoreRule {
+	file, err := os.Open(filepath.Join(root, dir, ".gitignore"))
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	var rules []ignoreRule
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		rule := ignoreRule{Base: filepath.ToSlash(dir)}
+		if strings.HasPrefix(line, "!") {
+			rule.Negate = true
+			line = line[1:]
+		}
+		line = strings.TrimPrefix(line, `\`)
+		if strings.HasSuffix(line, "/") {
+			rule.DirOnly = true
+			line = strings.TrimSuffix(line, "/")
+		}
+		if strings.Contains(line, "/") {
+			rule.Anchored = true
+			line = strings.TrimPrefix(line, "/")
+		}
+		rule.Pattern = line
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// matchSegments matches path segments against pattern segments, where "**" spans any number of segments
+func matchSegments(pattern, name []string) bool {
+	if len(pattern) == 0 {
+		return len(name) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(name); i++ {
+			if matchSegments(pattern[1:], name[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(name) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pattern[0], name[0]); !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], name[1:])
+}
+
+// matches reports whether the rule applies to relPath (slash-separated, relative to the scan root)
+func (rule ignoreRule) matches(relPath string, isDir bool) bool {
+	if rule.DirOnly && !isDir {
+		return false
+	}
+	if rule.Base != "." {
+		if !strings.HasPrefix(relPath, rule.Base+"/") {
+			return false
+		}
+		relPath = strings.TrimPrefix(relPath, rule.Base+"/")
+	}
+
+	if !rule.Anchored {
+		ok, _ := path.Match(rule.Pattern, path.Base(relPath))
+		return ok
+	}
+	return matchSegments(strings.Split(rule.Pattern, "/"), strings.Split(relPath, "/"))
+}
+
+// isIgnored applies the rules in order; the last matching rule wins, as in git
+func isIgnored(rules []ignoreRule, relPath string, isDir bool) bool {
+	ignored := false
+	for _, rule := range rules {
+		if rule.matches(relPath, isDir) {
+			ignored = !rule.Negate
+		}
+	}
+	return ignored
+}
+
+// --- Manifest Discovery ---
+
+// detectEcosystem maps a file to the auditor that understands it ("" if none)
+func detectEcosystem(relPath string) string {
+	name := path.Base(relPath)
+	lower := strings.ToLower(name)
+
+	switch {
+	case name == "package.json":
+		return ecosystemNpm
+	case name == "rebar.config":
+		return ecosystemRebar
+	case name == "Cargo.toml":
+		return ecosystemCargo
+	case name == "pom.xml" || strings.HasSuffix(name, ".versions.toml"):
+		return ecosystemMaven
+	case name == "go.mod":
+		return ecosystemGo
+	case lower == "dockerfile" || strings.HasPrefix(lower, "dockerfile.") || strings.HasSuffix(lower, ".dockerfile"):
+		return ecosystemDocker
+	case (strings.HasPrefix(lower, "docker-compose") || strings.HasPrefix(lower, "compose")) &&
+		(strings.HasSuffix(lower, ".yml") || strings.HasSuffix(lower, ".yaml")):
+		return ecosystemDocker
+	case (path.Dir(relPath) == workflowsDir || strings.HasSuffix(path.Dir(relPath), "/"+workflowsDir)) &&
+		(strings.HasSuffix(lower, ".yml") || strings.HasSuffix(lower, ".yaml")):
+		return ecosystemWorkflows
+	case name == "action.yml" || name == "action.yaml":
+		return ecosystemWorkflows
+	}
+	return ""
+}
+
+// findManifests walks root, honouring nested .gitignore files, and returns every supported manifest
+func findManifests(root string) ([]Manifest, error) {
+	var manifests []Manifest
+	var rules []ignoreRule
+	coveredCrates := make(map[string]bool) // Workspace members audited with their workspace root
+
+	err := filepath.WalkDir(root, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		relSlash := filepath.ToSlash(rel)
+
+		if d.IsDir() {
+			if rel != "." && (skippedDirs[d.Name()] || isIgnored(rules, relSlash, true)) {
+				return filepath.SkipDir
+			}
+			// WalkDir visits a directory before its children, so its rules apply to everything below
+			rules = append(rules, readIgnoreRules(root, rel)...)
+			return nil
+		}
+
+		if isIgnored(rules, relSlash, false) {
+			return nil
+		}
+
+		ecosystem := detectEcosystem(relSlash)
+		if ecosystem == "" {
+			return nil
+		}
+		if ecosystem == ecosystemCargo {
+			if coveredCrates[filepath.Clean(p)] {
+				return nil
+			}
+			for _, member := range cargo.WorkspaceMembers(p) {
+				coveredCrates[filepath.Clean(member)] = true
+			}
+		}
+		manifests = append(manifests, Manifest{Path: p, Ecosystem: ecosystem})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error walking %s: %w", root, err)
+	}
+	return manifests, nil
+}
+
+// --- Combined Report ---
+
+// scanManifest runs the matching auditor; its table is written into the combined report once the
+// findings are added to it
+func scanManifest(client *github.Client, router *forge.Router, manifest Manifest) ([]audit.Finding, audit.TableWriter, error) {
+	switch manifest.Ecosystem {
+	case ecosystemNpm:
+		return frontend.ScanManifest(router, manifest.Path)
+	case ecosystemRebar:
+		return backend.ScanManifest(router, manifest.Path)
+	case ecosystemCargo:
+		return cargo.ScanManifest(manifest.Path)
+	case ecosystemMaven:
+		return maven.ScanManifest(manifest.Path)
+	case ecosystemDocker:
+		return docker.ScanManifest(manifest.Path)
+	case ecosystemGo:
+		return gobinary.ScanGoMod(router, manifest.Path)
+	case ecosystemWorkflows:
+		return scanWorkflowFile(client, manifest.Path)
+	}
+	return nil, nil, fmt.Errorf("no auditor for ecosystem %s", manifest.Ecosystem)
+}
+
+// scanTemplate puts the combined summary and manifest list ahead of the per-manifest sections
+var scanTemplate = audit.Template{
+	Name: "scan",
+	Text: `# 🔍 Repository Dependency Scan
+
+This report combines the audits of the **{{len .Results.Manifests}}** manifests found under {{code .Results.Root}}.
+
+{{summary .Report}}{{range .Results.Manifests}}* {{code .Path}} ({{.Ecosystem}})
+{{end}}
+---
+
+{{.Results.Details}}`,
+}
+
+// ScanReport is the model of the scan report: the manifests found under Root and their rendered tables
+type ScanReport struct {
+	Root      string
+	Manifests []Manifest
+	Details   string // The per-manifest Markdown sections, in manifest order
+}
+
+// runScan audits every manifest under root and writes one report grouped by manifest location
+func runScan(root, outputFile string) error {
+	manifests, err := findManifests(root)
+	if err != nil {
+		return err
+	}
+	if len(manifests) == 0 {
+		fmt.Printf("No supported manifests found under %s.\n", root)
+		return nil
+	}
+
+	client := createGitHubClient()
+	router := forge.NewRouter(client)
+	report := audit.NewReport("scan", root)
+
+	// The manifest tables are collected first, so the summary can lead the report
+	var details bytes.Buffer
+	detailsWriter := bufio.NewWriter(&details)
+	for _, manifest := range manifests {
+		fmt.Printf("📁 Scanning %s (%s)...\n", manifest.Path, manifest.Ecosystem)
+		_, _ = detailsWriter.WriteString(fmt.Sprintf("## 📁 `%s` (%s)\n\n", manifest.Path, manifest.Ecosystem))
+		started := time.Now()
+		findings, table, err := scanManifest(client, router, manifest)
+		if err != nil {
+			fmt.Printf("⚠️ Warning: %v\n", err)
+			_, _ = detailsWriter.WriteString(fmt.Sprintf("> ❌ Error: %v\n", err))
+		}
+		report.Add(audit.NewSource(manifest.Path, manifest.Ecosystem, started, findings, err))
+		if table != nil {
+			table(detailsWriter, report.Sources[len(report.Sources)-1].Findings)
+		}
+		_, _ = detailsWriter.WriteString("\n---\n\n")
+	}
+	_ = detailsWriter.Flush()
+	report.Finish()
+
+	data := audit.TemplateData{Report: report, Results: ScanReport{Root: root, Manifests: manifests, Details: details.String()}}
+	if err := scanTemplate.Write(outputFile, data); err != nil {
+		return err
+	}
+	return report.WriteFiles(outputFile)
+}
@@ -0,0 +1,324 @@
+// Package version compares dependency versions under the scheme their ecosystem or
+// project uses: semantic versions, calendar versions, or plain dotted numbers of any length.
+package version
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/mod/semver"
+)
+
+// Scheme names a versioning scheme
+type Scheme string
+
+const (
+	SemVer  Scheme = "semver"  // MAJOR.MINOR.PATCH[-pre][+build], plus the v1 / v1.2 shorthands
+	CalVer  Scheme = "calver"  // YYYY.MM[.MICRO...] or YY.MM[...], e.g. 2024.03.1 or 24.04
+	Numeric Scheme = "numeric" // Any number of dot-separated numbers, e.g. 1.0 or 1.2.3.4
+)
+
+// ecosystemSchemes are the defaults per ecosystem. npm, Cargo and Go modules mandate semantic
+// versions; rebar, Maven, Docker tags and actions only follow them by convention.
+var ecosystemSchemes = map[string]Scheme{
+	"npm":            SemVer,
+	"cargo":          SemVer,
+	"go":             SemVer,
+	"github":         SemVer, // GitHub release tags of the repositories listed in input.txt
+	"rebar":          Numeric,
+	"maven":          Numeric,
+	"docker":         Numeric,
+	"github-actions": Numeric,
+}
+
+// --- Scheme Configuration ---
+
+// schemeConfigFile is the JSON layout of versions.json, e.g.
+// {"ecosystems": {"maven": "semver"}, "dependencies": {"rebar:p1_utils": "calver", "luerl": "numeric"}}
+type schemeConfigFile struct {
+	Ecosystems   map[string]Scheme `json:"ecosystems"`
+	Dependencies map[string]Scheme `json:"dependencies"` // Keyed by "ecosystem:name" or just "name"
+}
+
+var (
+	config     schemeConfigFile
+	configOnce sync.Once
+)
+
+// loadConfig: Reads the scheme overrides from VERSION_CONFIG (default versions.json), once
+func loadConfig() {
+	filename := os.Getenv("VERSION_CONFIG")
+	if filename == "" {
+		filename = "versions.json"
+	}
+	data, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return
+	}
+	if err != nil {
+		fmt.Printf("⚠️ Warning: error reading version config %s: %v\n", filename, err)
+		return
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		fmt.Printf("⚠️ Warning: error parsing version config %s: %v\n", filename, err)
+		config = schemeConfigFile{}
+		return
+	}
+
+	for key, scheme := range config.Ecosystems {
+		if !scheme.known() {
+			fmt.Printf("⚠️ Warning: Ignoring unknown version scheme '%s' for ecosystem %s\n", scheme, key)
+			delete(config.Ecosystems, key)
+		}
+	}
+	for key, scheme := range config.Dependencies {
+		if !scheme.known() {
+			fmt.Printf("⚠️ Warning: Ignoring unknown version scheme '%s' for dependency %s\n", scheme, key)
+			delete(config.Dependencies, key)
+		}
+	}
+}
+
+func (s Scheme) known() bool {
+	return s == SemVer || s == CalVer || s == Numeric
+}
+
+// For returns the scheme of a dependency: a per-dependency override, else the ecosystem's rule
+func For(ecosystem, dependency string) Scheme {
+	configOnce.Do(loadConfig)
+
+	if scheme, ok := config.Dependencies[ecosystem+":"+dependency]; ok {
+		return scheme
+	}
+	if scheme, ok := config.Dependencies[dependency]; ok {
+		return scheme
+	}
+	if scheme, ok := config.Ecosystems[ecosystem]; ok {
+		return scheme
+	}
+	if scheme, ok := ecosystemSchemes[ecosystem]; ok {
+		return scheme
+	}
+	return Numeric
+}
+
+// --- Parsing and Comparison ---
+
+// parsedVersion is a dotted-number version with an optional pre-release suffix
+type parsedVersion struct {
+	numbers    []int
+	prerelease string
+}
+
+// key: Identifies the version regardless of trailing zero components, so 1.0 and 1.0.0 are one release
+func (v parsedVersion) key() string {
+	numbers := v.numbers
+	for len(numbers) > 1 && numbers[len(numbers)-1] == 0 {
+		numbers = numbers[:len(numbers)-1]
+	}
+	return fmt.Sprint(numbers) + "-" + v.prerelease
+}
+
+// parseNumeric: Splits "v1.2.3.4-rc.1+build" into its numbers and pre-release; build metadata is ignored
+func parseNumeric(raw string) (parsedVersion, bool) {
+	raw = strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(raw), "v"), "V")
+	raw, _, _ = strings.Cut(raw, "+")
+	core, prerelease, _ := strings.Cut(raw, "-")
+
+	var result parsedVersion
+	for _, part := range strings.Split(core, ".") {
+		number, err := strconv.Atoi(part)
+		if err != nil {
+			return parsedVersion{}, false
+		}
+		result.numbers = append(result.numbers, number)
+	}
+	result.prerelease = prerelease
+	return result, true
+}
+
+// parse: Parses raw under the scheme's rules
+func (s Scheme) parse(raw string) (parsedVersion, bool) {
+	switch s {
+	case SemVer:
+		canonical := semver.Canonical(ensureV(strings.TrimSpace(raw)))
+		if canonical == "" {
+			return parsedVersion{}, false
+		}
+		return parseNumeric(canonical)
+	case CalVer:
+		parsed, ok := parseNumeric(raw)
+		if !ok || len(parsed.numbers) < 2 {
+			return parsedVersion{}, false
+		}
+		// The first component is the year, written with two or four digits
+		year := strings.TrimLeft(strings.Split(raw, ".")[0], "vV")
+		if len(year) != 2 && len(year) != 4 {
+			return parsedVersion{}, false
+		}
+		return parsed, true
+	default:
+		return parseNumeric(raw)
+	}
+}
+
+// Valid reports whether raw is a version under the scheme
+func (s Scheme) Valid(raw string) bool {
+	_, ok := s.parse(raw)
+	return ok
+}
+
+// Compare returns -1, 0 or +1 as a is lower, equal to or higher than b. Missing trailing
+// components count as zero (1.0 == 1.0.0), and a pre-release sorts before its release.
+// Invalid versions sort below all valid ones, as in golang.org/x/mod/semver.
+func (s Scheme) Compare(a, b string) int {
+	parsedA, okA := s.parse(a)
+	parsedB, okB := s.parse(b)
+	switch {
+	case !okA && !okB:
+		return 0
+	case !okA:
+		return -1
+	case !okB:
+		return 1
+	}
+
+	for i := 0; i < len(parsedA.numbers) || i < len(parsedB.numbers); i++ {
+		numberA, numberB := 0, 0
+		if i < len(parsedA.numbers) {
+			numberA = parsedA.numbers[i]
+		}
+		if i < len(parsedB.numbers) {
+			numberB = parsedB.numbers[i]
+		}
+		if numberA != numberB {
+			if numberA < numberB {
+				return -1
+			}
+			return 1
+		}
+	}
+	return comparePrerelease(parsedA.prerelease, parsedB.prerelease)
+}
+
+// comparePrerelease: Orders pre-release suffixes by semver precedence (rc.2 < rc.10), falling
+// back to plain string order for suffixes semver does not allow
+func comparePrerelease(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+	versionA, versionB := "v0.0.0-"+a, "v0.0.0-"+b
+	if semver.IsValid(versionA) && semver.IsValid(versionB) {
+		return semver.Compare(versionA, versionB)
+	}
+	return strings.Compare(a, b)
+}
+
+// Latest returns the highest valid version among tags, or "" when none is valid
+func (s Scheme) Latest(tags []string) string {
+	latest := ""
+	for _, tag := range tags {
+		if s.Valid(tag) && (latest == "" || s.Compare(tag, latest) > 0) {
+			latest = tag
+		}
+	}
+	return latest
+}
+
+func ensureV(raw string) string {
+	if !strings.HasPrefix(raw, "v") {
+		return "v" + raw
+	}
+	return raw
+}
+
+// --- Update Classification ---
+
+// Bump is the size of the step from one version to a newer one
+type Bump string
+
+const (
+	NoBump Bump = ""
+	Patch  Bump = "patch"
+	Minor  Bump = "minor"
+	Major  Bump = "major"
+)
+
+// Classify: Tells how big the step from current to latest is. Below 1.0 the leftmost non-zero
+// component acts as the major version, as npm and Cargo caret ranges treat it: 0.3 → 0.4 and
+// 0.0.3 → 0.0.4 are major, 0.3.1 → 0.3.2 is minor. A change in the pre-release only is a patch.
+func (s Scheme) Classify(current, latest string) Bump {
+	parsedCurrent, okCurrent := s.parse(current)
+	parsedLatest, okLatest := s.parse(latest)
+	if !okCurrent || !okLatest || s.Compare(latest, current) <= 0 {
+		return NoBump
+	}
+
+	lead := 0
+	for lead < len(parsedCurrent.numbers)-1 && parsedCurrent.numbers[lead] == 0 {
+		lead++
+	}
+
+	for i := 0; i < len(parsedCurrent.numbers) || i < len(parsedLatest.numbers); i++ {
+		numberCurrent, numberLatest := 0, 0
+		if i < len(parsedCurrent.numbers) {
+			numberCurrent = parsedCurrent.numbers[i]
+		}
+		if i < len(parsedLatest.numbers) {
+			numberLatest = parsedLatest.numbers[i]
+		}
+		if numberCurrent == numberLatest {
+			continue
+		}
+		switch {
+		case i <= lead:
+			return Major
+		case i == lead+1:
+			return Minor
+		default:
+			return Patch
+		}
+	}
+	return Patch
+}
+
+// Breaking reports whether the bump may carry breaking changes
+func (b Bump) Breaking() bool {
+	return b == Major
+}
+
+// Behind counts the releases among versions that are newer than current, up to and including
+// latest. Pre-releases only count when latest is one itself, and tags naming the same version
+// (v1.0, 1.0.0) count once.
+func (s Scheme) Behind(current, latest string, versions []string) int {
+	parsedLatest, ok := s.parse(latest)
+	if !ok {
+		return 0
+	}
+
+	seen := make(map[string]bool)
+	count := 0
+	for _, candidate := range versions {
+		parsed, ok := s.parse(candidate)
+		if !ok || (parsed.prerelease != "" && parsedLatest.prerelease == "") {
+			continue
+		}
+		if seen[parsed.key()] {
+			continue
+		}
+		seen[parsed.key()] = true
+		if s.Compare(candidate, current) > 0 && s.Compare(candidate, latest) <= 0 {
+			count++
+		}
+	}
+	return count
+}
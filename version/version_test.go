@@ -0,0 +1,168 @@
+package version
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestParseCalVer(t *testing.T) {
+	tests := []struct {
+		raw   string
+		valid bool
+	}{
+		{"2024.01", true},
+		{"2024.1.15", true},
+		{"v2024.03.1", true},
+		{"24.04", true},
+		{"24.04.1-rc1", true},
+		{"2024", false},
+		{"224.1", false},
+		{"20240.1", false},
+		{"2024.x", false},
+	}
+	for _, test := range tests {
+		t.Run(test.raw, func(t *testing.T) {
+			if _, ok := CalVer.parse(test.raw); ok != test.valid {
+				t.Errorf("CalVer.parse(%q) valid = %v, want %v", test.raw, ok, test.valid)
+			}
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		scheme Scheme
+		a, b   string
+		want   int
+	}{
+		{SemVer, "1.0", "1.0.0", 0},
+		{SemVer, "v1.2.3", "1.2.3", 0},
+		{SemVer, "1.2.3", "1.10.0", -1},
+		{SemVer, "1.0.0-rc.2", "1.0.0-rc.10", -1},
+		{SemVer, "1.0.0-alpha", "1.0.0-beta", -1},
+		{SemVer, "1.0.0-rc.1", "1.0.0", -1},
+		{SemVer, "not-a-version", "0.0.1", -1},
+		{SemVer, "garbage", "rubbish", 0},
+		{Numeric, "1.0", "1.0.0", 0},
+		{Numeric, "1.2.3.4", "1.2.3.10", -1},
+		{Numeric, "2.0-rc1", "2.0", -1},
+		{CalVer, "24.04", "24.10", -1},
+		{CalVer, "2024.1", "2024.01.0", 0},
+		{CalVer, "2023.12.1", "2024.01", -1},
+	}
+	for _, test := range tests {
+		t.Run(string(test.scheme)+" "+test.a+" vs "+test.b, func(t *testing.T) {
+			if got := test.scheme.Compare(test.a, test.b); got != test.want {
+				t.Errorf("Compare(%q, %q) = %d, want %d", test.a, test.b, got, test.want)
+			}
+			if got := test.scheme.Compare(test.b, test.a); got != -test.want {
+				t.Errorf("Compare(%q, %q) = %d, want %d", test.b, test.a, got, -test.want)
+			}
+		})
+	}
+}
+
+// withConfig: Replaces the scheme overrides for one test, skipping the versions.json lookup
+func withConfig(t *testing.T, overrides schemeConfigFile) {
+	t.Helper()
+	configOnce.Do(func() {})
+	saved := config
+	config = overrides
+	t.Cleanup(func() {
+		config = saved
+		configOnce = sync.Once{}
+	})
+}
+
+func TestFor(t *testing.T) {
+	withConfig(t, schemeConfigFile{
+		Ecosystems: map[string]Scheme{"maven": SemVer},
+		Dependencies: map[string]Scheme{
+			"rebar:p1_utils": CalVer,
+			"p1_utils":       SemVer,
+			"luerl":          Numeric,
+			"guava":          CalVer,
+		},
+	})
+	tests := []struct {
+		ecosystem, dependency string
+		want                  Scheme
+	}{
+		{"rebar", "p1_utils", CalVer},   // "ecosystem:name" wins over "name"
+		{"npm", "p1_utils", SemVer},     // "name" applies to every ecosystem
+		{"npm", "luerl", Numeric},       // "name" wins over the ecosystem default
+		{"maven", "guava", CalVer},      // "name" wins over the configured ecosystem
+		{"maven", "commons-io", SemVer}, // The configured ecosystem wins over the default
+		{"rebar", "cowboy", Numeric},
+		{"cargo", "serde", SemVer},
+		{"github", "acme/lib", SemVer},
+		{"unknown", "thing", Numeric},
+	}
+	for _, test := range tests {
+		t.Run(test.ecosystem+":"+test.dependency, func(t *testing.T) {
+			if got := For(test.ecosystem, test.dependency); got != test.want {
+				t.Errorf("For(%q, %q) = %q, want %q", test.ecosystem, test.dependency, got, test.want)
+			}
+		})
+	}
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		scheme          Scheme
+		current, latest string
+		want            Bump
+	}{
+		{SemVer, "1.2.3", "2.0.0", Major},
+		{SemVer, "1.2.3", "1.3.0", Minor},
+		{SemVer, "1.2.3", "1.2.4", Patch},
+		{SemVer, "0.3.0", "0.4.0", Major},
+		{SemVer, "0.3", "0.4", Major},
+		{SemVer, "0.0.3", "0.0.4", Major},
+		{SemVer, "0.3.1", "0.3.2", Minor},
+		{SemVer, "0.3.1", "1.0.0", Major},
+		{SemVer, "1.0.0-rc.1", "1.0.0", Patch},
+		{SemVer, "1.0.0-rc.1", "1.0.0-rc.2", Patch},
+		{SemVer, "v1.2.3", "1.2.3", NoBump},
+		{SemVer, "1.0", "1.0.0", NoBump},
+		{SemVer, "2.0.0", "1.9.9", NoBump},
+		{SemVer, "latest", "1.0.0", NoBump},
+		{Numeric, "1.2.3.4", "1.2.3.5", Patch},
+		{Numeric, "1.2", "1.2.0.1", Patch},
+		{CalVer, "2024.01", "2025.01", Major},
+		{CalVer, "24.04", "24.10", Minor},
+	}
+	for _, test := range tests {
+		t.Run(string(test.scheme)+" "+test.current+" to "+test.latest, func(t *testing.T) {
+			if got := test.scheme.Classify(test.current, test.latest); got != test.want {
+				t.Errorf("Classify(%q, %q) = %q, want %q", test.current, test.latest, got, test.want)
+			}
+		})
+	}
+}
+
+func TestBehind(t *testing.T) {
+	tests := []struct {
+		name            string
+		current, latest string
+		versions        []string
+		want            int
+	}{
+		{"newer releases", "1.0.0", "1.2.0", []string{"0.9.0", "1.0.0", "1.1.0", "1.2.0"}, 2},
+		{"up to date", "1.2.0", "1.2.0", []string{"1.0.0", "1.1.0", "1.2.0"}, 0},
+		{"beyond latest ignored", "1.0.0", "1.1.0", []string{"1.1.0", "1.2.0", "2.0.0"}, 1},
+		{"pre-releases skipped", "1.0.0", "1.1.0", []string{"1.1.0-rc.1", "1.1.0"}, 1},
+		{"pre-releases counted", "1.0.0", "1.1.0-rc.2", []string{"1.1.0-rc.1", "1.1.0-rc.2"}, 2},
+		{"v prefix duplicates", "1.0.0", "1.2.0", []string{"v1.1.0", "1.1.0", "v1.2.0", "1.2.0"}, 2},
+		{"trailing zero duplicates", "1.0.0", "2.0.0", []string{"1.1", "1.1.0", "v1.1.0", "2.0", "2.0.0"}, 2},
+		{"invalid tags ignored", "1.0.0", "1.1.0", []string{"nightly", "1.1.0"}, 1},
+		{"invalid latest", "1.0.0", "nightly", []string{"1.1.0"}, 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := SemVer.Behind(test.current, test.latest, test.versions); got != test.want {
+				t.Errorf("Behind(%q, %q, %v) = %d, want %d", test.current, test.latest, test.versions, got, test.want)
+			}
+		})
+	}
+}
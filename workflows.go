@@ -0,0 +1,323 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"Sbom/audit"
+	"Sbom/changelog"
+	"Sbom/version"
+
+	"github.com/google/go-github/v62/github"
+)
+
+// ActionReference is a single `uses: owner/repo@ref` line found in a workflow or composite action
+type ActionReference struct {
+	File       string
+	Line       int
+	Owner      string
+	Repo       string
+	Path       string // Sub-directory for actions such as github/codeql-action/init
+	Ref        string
+	RefComment string // Version noted after a pinned SHA, e.g. "# v4.1.1"
+	Pinning    string
+}
+
+// WorkflowFinding pairs an action reference with its update status
+type WorkflowFinding struct {
+	Action ActionReference
+	Info   UpdateInfo
+}
+
+const (
+	pinnedSHA    = "🔒 Commit SHA"
+	mutableTag   = "⚠️ Mutable tag"
+	mutableRef   = "❌ Branch / mutable ref"
+	workflowsDir = ".github/workflows"
+)
+
+var usesPattern = regexp.MustCompile(`^\s*-?\s*uses:\s*["']?([^"'\s#]+)["']?\s*(?:#\s*(.*))?$`)
+var shaPattern = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// findWorkflowFiles collects workflow files and composite action definitions under root
+func findWorkflowFiles(root string) ([]string, error) {
+	var files []string
+
+	workflows, err := filepath.Glob(filepath.Join(root, workflowsDir, "*.y*ml"))
+	if err != nil {
+		return nil, fmt.Errorf("error listing workflows: %w", err)
+	}
+	files = append(files, workflows...)
+
+	// Composite actions may live anywhere in the repository (usually .github/actions/<name>/action.yml)
+	err = filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && (d.Name() == "node_modules" || (d.Name() == ".git" && path != root)) {
+			return filepath.SkipDir
+		}
+		if !d.IsDir() && (d.Name() == "action.yml" || d.Name() == "action.yaml") {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error walking %s: %w", root, err)
+	}
+	return files, nil
+}
+
+// parseActionReferences extracts remote `uses:` references; local (./) and docker:// actions are skipped
+func parseActionReferences(filename string) ([]ActionReference, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("error opening workflow file: %w", err)
+	}
+	defer file.Close()
+
+	var refs []ActionReference
+	scanner := bufio.NewScanner(file)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		match := usesPattern.FindStringSubmatch(scanner.Text())
+		if match == nil {
+			continue
+		}
+
+		uses := match[1]
+		if strings.HasPrefix(uses, "./") || strings.HasPrefix(uses, "docker://") {
+			continue
+		}
+
+		target, ref, found := strings.Cut(uses, "@")
+		if !found {
+			continue
+		}
+		parts := strings.SplitN(target, "/", 3)
+		if len(parts) < 2 {
+			continue
+		}
+
+		action := ActionReference{
+			File:       filename,
+			Line:       lineNumber,
+			Owner:      parts[0],
+			Repo:       parts[1],
+			Ref:        ref,
+			RefComment: strings.TrimSpace(match[2]),
+			Pinning:    classifyRef(ref),
+		}
+		if len(parts) == 3 {
+			action.Path = parts[2]
+		}
+		refs = append(refs, action)
+	}
+	return refs, scanner.Err()
+}
+
+// classifyRef tells full commit SHAs apart from tags and branches, which can be moved
+func classifyRef(ref string) string {
+	if shaPattern.MatchString(ref) {
+		return pinnedSHA
+	}
+	if version.For("github-actions", "").Valid(ref) {
+		return mutableTag
+	}
+	return mutableRef
+}
+
+func ensureV(ver string) string {
+	if !strings.HasPrefix(ver, "v") {
+		return "v" + ver
+	}
+	return ver
+}
+
+// checkActionUpdate runs checkUpdate for the version an action is pinned to
+func checkActionUpdate(client *github.Client, action ActionReference) UpdateInfo {
+	tag := action.Ref
+	scheme := version.For("github-actions", action.Owner+"/"+action.Repo)
+	if action.Pinning == pinnedSHA {
+		// A SHA only has a known version when it is annotated, e.g. `@<sha> # v4.1.1`
+		tag = strings.Fields(action.RefComment + " ")[0]
+		if !scheme.Valid(tag) {
+			return UpdateInfo{
+				Repo:           action.Owner + "/" + action.Repo,
+				CurrentVersion: action.Ref[:7],
+				LatestVersion:  "N/A",
+				Status:         "📌 Pinned to commit (version unknown)",
+			}
+		}
+	}
+	if !scheme.Valid(tag) {
+		return UpdateInfo{
+			Repo:           action.Owner + "/" + action.Repo,
+			CurrentVersion: action.Ref,
+			LatestVersion:  "N/A",
+			Status:         "⚠️ Branch reference (always latest, not reproducible)",
+		}
+	}
+
+	currentVer := ensureV(tag)
+	info := checkUpdate(client, action.Owner, action.Repo, currentVer, scheme)
+
+	// Floating tags (@v4, @v4.1) track every matching release, so only a newer major/minor is an update
+	if info.UpdateNeeded && strings.Count(currentVer, ".") < 2 && strings.HasPrefix(info.LatestVersion, currentVer+".") {
+		info.UpdateNeeded = false
+		info.SecurityPatch = false
+		info.Changelog, info.BreakingChanges = changelog.Notes{}, nil
+		info.Bump, info.ReleasesBehind = version.NoBump, 0
+		info.Status = "✅ Up to date (floating tag)"
+	}
+	return info
+}
+
+// workflowTemplate is the default action report, leading with how many actions are pinned to a SHA
+var workflowTemplate = audit.Template{
+	Name: "workflows",
+	Text: `# ⚙️ GitHub Actions Dependency Report
+
+This report lists every action referenced by your workflows and composite actions.
+
+* Actions pinned to a full commit SHA: **{{pinned .Results}} / {{len .Results}}**
+{{summary .Report}}> Tags and branches can be moved by the action's owner; pin to a commit SHA (with a {{code "# vX.Y.Z"}} comment) for reproducible, tamper-resistant builds.
+
+---
+
+{{table .Results .Report.Findings}}`,
+	Funcs: map[string]interface{}{
+		"pinned": func(findings []WorkflowFinding) int {
+			pinned := 0
+			for _, finding := range findings {
+				if finding.Action.Pinning == pinnedSHA {
+					pinned++
+				}
+			}
+			return pinned
+		},
+		"table": func(results []WorkflowFinding, findings []audit.Finding) string {
+			return audit.Capture(func(writer *bufio.Writer) { writeWorkflowTable(writer, results, findings) })
+		},
+	},
+}
+
+// writeWorkflowOutput renders the workflow audit ([]WorkflowFinding) through workflowTemplate
+func writeWorkflowOutput(findings []WorkflowFinding, report *audit.Report, filename string) error {
+	return workflowTemplate.Write(filename, audit.TemplateData{Report: report, Results: findings})
+}
+
+// writeWorkflowTable writes the action table (shared with combined scan reports) from the report's
+// findings; results, in the same order, add the locations, refs and pinning
+func writeWorkflowTable(writer *bufio.Writer, results []WorkflowFinding, findings []audit.Finding) {
+	_, _ = writer.WriteString("| # | Location | Action | Ref | Pinning | Status | Change | Current Version | Latest Version |\n")
+	_, _ = writer.WriteString("| :---: | :--- | :--- | :--- | :---: | :---: | :---: | :---: | :---: |\n")
+
+	for i, finding := range findings {
+		action := results[i].Action
+		name := finding.Name
+		if action.Path != "" {
+			name += "/" + action.Path
+		}
+		_, _ = writer.WriteString(fmt.Sprintf("| %d | `%s:%d` | [`%s`](https://github.com/%s/%s) | `%s` | %s | %s | %s | `%s` | `%s` |\n",
+			i+1, action.File, action.Line, name, action.Owner, action.Repo, action.Ref, action.Pinning,
+			audit.AcceptedStatus(finding.Status, finding), audit.ChangeLabel(finding.Bump, finding.ReleasesBehind), finding.CurrentVersion, finding.LatestVersion))
+	}
+}
+
+// runWorkflowAudit scans the workflows under root and writes the report
+func runWorkflowAudit(root, outputFile string) error {
+	files, err := findWorkflowFiles(root)
+	if err != nil {
+		return err
+	}
+
+	var actions []ActionReference
+	for _, file := range files {
+		refs, err := parseActionReferences(file)
+		if err != nil {
+			fmt.Printf("⚠️ Warning: %v\n", err)
+			continue
+		}
+		actions = append(actions, refs...)
+	}
+
+	if len(actions) == 0 {
+		fmt.Printf("No action references found under %s.\n", root)
+		return nil
+	}
+
+	client := createGitHubClient()
+
+	fmt.Printf("Starting check for %d action references in %d files...\n", len(actions), len(files))
+	report := audit.NewReport("workflows", root)
+	started := time.Now()
+	findings := checkActions(client, actions)
+
+	// Each workflow file is one source; the checks are shared between them, so the time is the total
+	for _, file := range files {
+		var fileFindings []WorkflowFinding
+		for _, finding := range findings {
+			if finding.Action.File == file {
+				fileFindings = append(fileFindings, finding)
+			}
+		}
+		if len(fileFindings) > 0 {
+			report.Add(audit.NewSource(file, ecosystemWorkflows, started, toWorkflowFindings(fileFindings), nil))
+		}
+	}
+	report.Finish()
+
+	if err := writeWorkflowOutput(findings, report, outputFile); err != nil {
+		return err
+	}
+	return report.WriteFiles(outputFile)
+}
+
+// checkActions checks each action reference; the same action@ref is usually repeated across jobs, so it is checked once
+func checkActions(client *github.Client, actions []ActionReference) []WorkflowFinding {
+	checked := make(map[string]UpdateInfo)
+	var findings []WorkflowFinding
+
+	for _, action := range actions {
+		key := action.Owner + "/" + action.Repo + "@" + action.Ref + "#" + action.RefComment
+		info, ok := checked[key]
+		if !ok {
+			fmt.Printf("-> Checking %s/%s@%s (%s)...\n", action.Owner, action.Repo, action.Ref, action.Pinning)
+			info = checkActionUpdate(client, action)
+			checked[key] = info
+		}
+		findings = append(findings, WorkflowFinding{Action: action, Info: info})
+	}
+	return findings
+}
+
+// scanWorkflowFile audits one workflow or composite action file for combined scan reports; its
+// table is written once the findings are added to the report
+func scanWorkflowFile(client *github.Client, filename string) ([]audit.Finding, audit.TableWriter, error) {
+	actions, err := parseActionReferences(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	results := checkActions(client, actions)
+	table := func(writer *bufio.Writer, findings []audit.Finding) { writeWorkflowTable(writer, results, findings) }
+	return toWorkflowFindings(results), table, nil
+}
+
+// toWorkflowFindings converts the checked action references into the ecosystem-neutral model
+func toWorkflowFindings(results []WorkflowFinding) []audit.Finding {
+	var findings []audit.Finding
+	for _, finding := range results {
+		converted := toFinding(finding.Info)
+		converted.Name = finding.Action.Owner + "/" + finding.Action.Repo
+		converted.Line = finding.Action.Line
+		findings = append(findings, converted)
+	}
+	return findings
+}